@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/textproto"
+	"strconv"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	v1 "github.com/kube-orchestra/maestro/proto/api/v1"
+	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// newGateway returns the HTTP gateway of the services served by conn.
+func newGateway(ctx context.Context, conn *grpc.ClientConn) (*runtime.ServeMux, error) {
+	gwmux := runtime.NewServeMux(
+		runtime.WithForwardResponseOption(setETag),
+		runtime.WithErrorHandler(errorHandler),
+		runtime.WithIncomingHeaderMatcher(incomingHeaderMatcher),
+	)
+
+	if err := v1.RegisterConsumerServiceHandler(ctx, gwmux, conn); err != nil {
+		return nil, fmt.Errorf("failed to register consumer service handler: %w", err)
+	}
+	if err := v1.RegisterResourceServiceHandler(ctx, gwmux, conn); err != nil {
+		return nil, fmt.Errorf("failed to register resource service handler: %w", err)
+	}
+	if err := v1.RegisterDeadLetterServiceHandler(ctx, gwmux, conn); err != nil {
+		return nil, fmt.Errorf("failed to register dead letter service handler: %w", err)
+	}
+	return gwmux, nil
+}
+
+// setETag exposes the generation of the returned resource as its ETag,
+// to be sent back in the If-Match header of conditional updates.
+func setETag(_ context.Context, w http.ResponseWriter, resp proto.Message) error {
+	if res, ok := resp.(*v1.Resource); ok {
+		w.Header().Set("ETag", strconv.Quote(strconv.FormatInt(res.GenerationId, 10)))
+	}
+	return nil
+}
+
+// incomingHeaderMatcher forwards the Idempotency-Key header to the services, besides the default ones.
+func incomingHeaderMatcher(key string) (string, bool) {
+	if textproto.CanonicalMIMEHeaderKey(key) == "Idempotency-Key" {
+		return "idempotency-key", true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// errorHandler renders errors as an ErrorResponse, and reports the failure of an If-Match
+// precondition as 412 Precondition Failed instead of the 409 Conflict ABORTED maps to.
+func errorHandler(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	httpStatus := runtime.HTTPStatusFromCode(status.Code(err))
+	var customStatus *runtime.HTTPStatusError
+	if errors.As(err, &customStatus) {
+		httpStatus = customStatus.HTTPStatus
+	}
+
+	if r.Header.Get("If-Match") != "" && status.Code(err) == codes.Aborted {
+		httpStatus = http.StatusPreconditionFailed
+		w = &statusCodeWriter{ResponseWriter: w, statusCode: httpStatus}
+	}
+
+	runtime.DefaultHTTPErrorHandler(ctx, mux, &errorMarshaler{Marshaler: marshaler, httpStatus: httpStatus}, w, r, err)
+}
+
+// errorMarshaler marshals the status of the errors as an ErrorResponse.
+type errorMarshaler struct {
+	runtime.Marshaler
+	httpStatus int
+}
+
+func (m *errorMarshaler) Marshal(v interface{}) ([]byte, error) {
+	st, ok := v.(*spb.Status)
+	if !ok {
+		return m.Marshaler.Marshal(v)
+	}
+
+	e := &v1.Error{
+		Code:    int32(m.httpStatus),
+		Status:  code.Code(st.Code).String(),
+		Message: st.Message,
+		Details: st.Details,
+	}
+	for _, detail := range status.FromProto(st).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			e.Reason = info.Reason
+			e.Metadata = info.Metadata
+			break
+		}
+	}
+	if e.Reason == "" {
+		e.Reason = e.Status
+	}
+
+	return m.Marshaler.Marshal(&v1.ErrorResponse{Error: e})
+}
+
+// statusCodeWriter replaces the status code written by the wrapped handler.
+type statusCodeWriter struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (w *statusCodeWriter) WriteHeader(int) {
+	w.ResponseWriter.WriteHeader(w.statusCode)
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kube-orchestra/maestro/internal/db"
+	"github.com/kube-orchestra/maestro/internal/rpcerror"
+	resourcesv1 "github.com/kube-orchestra/maestro/internal/service/v1/resources"
+	"github.com/kube-orchestra/maestro/internal/validation"
+	"github.com/kube-orchestra/maestro/internal/watch"
+	v1 "github.com/kube-orchestra/maestro/proto/api/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newTestGateway returns the gateway of a resource service storing in a memory store with consumer c1.
+func newTestGateway(t *testing.T) http.Handler {
+	t.Helper()
+
+	store := db.NewMemoryStore()
+	if err := store.CreateConsumer(&v1.Consumer{Id: "c1"}); err != nil {
+		t.Fatal(err)
+	}
+	validator, err := validation.NewValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(rpcerror.UnaryServerInterceptor),
+		grpc.ChainStreamInterceptor(rpcerror.StreamServerInterceptor),
+	)
+	v1.RegisterResourceServiceServer(s, resourcesv1.NewResourceService(store, watch.NewHub(),
+		make(chan db.ResourceMessage, 100), 10, validator, resourcesv1.UnknownKindsReject, time.Hour))
+
+	lis := bufconn.Listen(1 << 20)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	gwmux, err := newGateway(context.Background(), conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return gwmux
+}
+
+func configMap(data string) string {
+	return `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "config", "namespace": "default"}, "data": {"key": "` + data + `"}}`
+}
+
+func serve(t *testing.T, gw http.Handler, method, path, ifMatch, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	r := httptest.NewRequest(method, path, strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	if ifMatch != "" {
+		r.Header.Set("If-Match", ifMatch)
+	}
+	w := httptest.NewRecorder()
+	gw.ServeHTTP(w, r)
+	return w
+}
+
+// createTestResource creates a ConfigMap through the gateway and returns its ID.
+func createTestResource(t *testing.T, gw http.Handler) string {
+	t.Helper()
+
+	w := serve(t, gw, http.MethodPost, "/v1/consumers/c1/resources", "", configMap("a"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d creating a resource: %s", w.Code, w.Body)
+	}
+	if etag := w.Header().Get("ETag"); etag != `"1"` {
+		t.Errorf("got ETag %s on creation, want \"1\"", etag)
+	}
+
+	var res struct{ Id string }
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+	return res.Id
+}
+
+// errorResponse returns the error of the body of an error response.
+func errorResponse(t *testing.T, w *httptest.ResponseRecorder) *v1.Error {
+	t.Helper()
+
+	var response struct {
+		Error struct {
+			Code   int32
+			Status string
+			Reason string
+		}
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to read the error response %s: %v", w.Body, err)
+	}
+	return &v1.Error{Code: response.Error.Code, Status: response.Error.Status, Reason: response.Error.Reason}
+}
+
+func TestETag(t *testing.T) {
+	gw := newTestGateway(t)
+	id := createTestResource(t, gw)
+
+	w := serve(t, gw, http.MethodGet, "/v1/resources/"+id, "", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d reading the resource: %s", w.Code, w.Body)
+	}
+	if etag := w.Header().Get("ETag"); etag != `"1"` {
+		t.Errorf("got ETag %s reading the resource, want \"1\"", etag)
+	}
+
+	w = serve(t, gw, http.MethodGet, "/v1/resources", "", "")
+	if etag := w.Header().Get("ETag"); etag != "" {
+		t.Errorf("got ETag %s on a list, want none", etag)
+	}
+}
+
+func TestIfMatch(t *testing.T) {
+	gw := newTestGateway(t)
+	id := createTestResource(t, gw)
+
+	w := serve(t, gw, http.MethodPut, "/v1/resources/"+id, `"1"`, configMap("b"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d updating the resource at its ETag: %s", w.Code, w.Body)
+	}
+	if etag := w.Header().Get("ETag"); etag != `"2"` {
+		t.Errorf("got ETag %s after the update, want \"2\"", etag)
+	}
+
+	// the weak form and the wildcard match as well
+	for i, ifMatch := range []string{`W/"2"`, "*"} {
+		w = serve(t, gw, http.MethodPut, "/v1/resources/"+id, ifMatch, configMap(strconv.Itoa(i)))
+		if w.Code != http.StatusOK {
+			t.Errorf("got status %d updating the resource if it matches %s: %s", w.Code, ifMatch, w.Body)
+		}
+	}
+
+	w = serve(t, gw, http.MethodPut, "/v1/resources/"+id, "not an etag", configMap("d"))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("got status %d with an invalid If-Match header, want 400: %s", w.Code, w.Body)
+	}
+}
+
+func TestIfMatchPreconditionFailed(t *testing.T) {
+	gw := newTestGateway(t)
+	id := createTestResource(t, gw)
+
+	if w := serve(t, gw, http.MethodPut, "/v1/resources/"+id, "", configMap("b")); w.Code != http.StatusOK {
+		t.Fatalf("got status %d updating the resource: %s", w.Code, w.Body)
+	}
+
+	w := serve(t, gw, http.MethodPut, "/v1/resources/"+id, `"1"`, configMap("c"))
+	if w.Code != http.StatusPreconditionFailed {
+		t.Fatalf("got status %d updating with a stale ETag, want 412: %s", w.Code, w.Body)
+	}
+	e := errorResponse(t, w)
+	if e.Code != http.StatusPreconditionFailed || e.Status != "ABORTED" || e.Reason != rpcerror.ReasonGenerationMismatch {
+		t.Errorf("got error %v, want a 412 ABORTED %s", e, rpcerror.ReasonGenerationMismatch)
+	}
+
+	// without If-Match, the same conflict is a 409
+	w = serve(t, gw, http.MethodPut, "/v1/resources/"+id+"?expectedGenerationId=1", "", configMap("c"))
+	if w.Code != http.StatusConflict {
+		t.Errorf("got status %d updating with a stale expectedGenerationId, want 409: %s", w.Code, w.Body)
+	}
+	if e := errorResponse(t, w); e.Code != http.StatusConflict || e.Reason != rpcerror.ReasonGenerationMismatch {
+		t.Errorf("got error %v, want a 409 %s", e, rpcerror.ReasonGenerationMismatch)
+	}
+}
@@ -2,14 +2,22 @@ package main
 
 import (
 	"context"
+	"expvar"
+	"flag"
 	"log"
 	"net"
 	"net/http"
+	"os"
+	"time"
 
-	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/kube-orchestra/maestro/internal/db"
 	"github.com/kube-orchestra/maestro/internal/mqtt"
+	"github.com/kube-orchestra/maestro/internal/rpcerror"
 	consumerv1 "github.com/kube-orchestra/maestro/internal/service/v1/consumers"
+	deadlettersv1 "github.com/kube-orchestra/maestro/internal/service/v1/deadletters"
 	resourcesv1 "github.com/kube-orchestra/maestro/internal/service/v1/resources"
+	"github.com/kube-orchestra/maestro/internal/validation"
+	"github.com/kube-orchestra/maestro/internal/watch"
 	v1 "github.com/kube-orchestra/maestro/proto/api/v1"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
@@ -19,10 +27,55 @@ import (
 const listenAddress = "0.0.0.0:8080"
 const listenAddressGateway = "0.0.0.0:8090"
 
+// storeTypeEnv sets the default for the --store flag.
+const storeTypeEnv = "STORE_TYPE"
+
 func main() {
-	mqttConnection := mqtt.NewConnection()
+	defaultStoreType := os.Getenv(storeTypeEnv)
+	if len(defaultStoreType) == 0 {
+		defaultStoreType = db.StoreDynamoDB
+	}
+	storeType := flag.String("store", defaultStoreType, "storage backend: dynamodb, memory, postgres or sqlite")
+	revisionHistoryLimit := flag.Int("revision-history-limit", 10, "number of revisions kept per resource")
+	unknownKinds := flag.String("unknown-kinds", resourcesv1.UnknownKindsReject,
+		"what happens to the resources of kinds unknown to their consumer: reject or warn")
+	idempotencyWindow := flag.Duration("idempotency-window", 24*time.Hour,
+		"how long the responses to create requests with an idempotency key are kept for their retries")
+	deadLetterRetention := flag.Duration("dead-letter-retention", 7*24*time.Hour,
+		"how long the status messages rejected by maestro are kept")
+	flag.Parse()
+
+	if *revisionHistoryLimit < 1 {
+		log.Fatalln("--revision-history-limit must be at least 1")
+	}
+	if *unknownKinds != resourcesv1.UnknownKindsReject && *unknownKinds != resourcesv1.UnknownKindsWarn {
+		log.Fatalf("unknown --unknown-kinds value %q, expected reject or warn", *unknownKinds)
+	}
+	if *idempotencyWindow <= 0 {
+		log.Fatalln("--idempotency-window must be positive")
+	}
+	if *deadLetterRetention <= 0 {
+		log.Fatalln("--dead-letter-retention must be positive")
+	}
+
+	dbStore, err := db.NewStore(*storeType)
+	if err != nil {
+		log.Fatalln("Failed to create store:", err)
+	}
+
+	validator, err := validation.NewValidator()
+	if err != nil {
+		log.Fatalln("Failed to load the OpenAPI schemas:", err)
+	}
+
+	// publish every change made to the store to the watch APIs
+	hub := watch.NewHub()
+	store := watch.NewStore(dbStore, hub)
+
+	mqttConnection := mqtt.NewConnection(store, *deadLetterRetention)
 	mqttConnection.StartSender()
 	mqttConnection.StartStatusReceiver()
+	mqttConnection.StartDeadLetterCleanup()
 
 	// gRPC config
 
@@ -33,17 +86,24 @@ func main() {
 	}
 
 	// Create a gRPC server object
-	s := grpc.NewServer()
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(rpcerror.UnaryServerInterceptor),
+		grpc.ChainStreamInterceptor(rpcerror.StreamServerInterceptor),
+	)
 	// Register reflection service on gRPC server.
 	reflection.Register(s)
 
+	// Attach the resources service to the server
+	var resourcesAPI = resourcesv1.NewResourceService(store, hub, mqttConnection.ResourceChannel, *revisionHistoryLimit, validator, *unknownKinds, *idempotencyWindow)
+	resourcesAPI.StartIdempotencyCleanup()
+	v1.RegisterResourceServiceServer(s, resourcesAPI)
+
 	// Attach the consumers service to the server
-	var consumersAPI = consumerv1.NewConsumerService()
+	var consumersAPI = consumerv1.NewConsumerService(store, hub, resourcesAPI, resourcesAPI)
 	v1.RegisterConsumerServiceServer(s, consumersAPI)
 
-	// Attach the resources service to the server
-	var resourcesAPI = resourcesv1.NewResourceService(mqttConnection.ResourceChannel)
-	v1.RegisterResourceServiceServer(s, resourcesAPI)
+	// Attach the dead letters service to the server
+	v1.RegisterDeadLetterServiceServer(s, deadlettersv1.NewDeadLetterService(store))
 
 	// Serve gRPC server
 	log.Println("Serving gRPC on", listenAddress)
@@ -63,17 +123,9 @@ func main() {
 		log.Fatalln("Failed to dial server:", err)
 	}
 
-	gwmux := runtime.NewServeMux()
-
-	// Register Greeter
-	err = v1.RegisterConsumerServiceHandler(context.Background(), gwmux, conn)
-	if err != nil {
-		log.Fatalln("Failed to register consumer service handler:", err)
-	}
-
-	err = v1.RegisterResourceServiceHandler(context.Background(), gwmux, conn)
+	gwmux, err := newGateway(context.Background(), conn)
 	if err != nil {
-		log.Fatalln("Failed to register resource service handler:", err)
+		log.Fatalln(err)
 	}
 
 	mux := http.NewServeMux()
@@ -89,6 +141,14 @@ func main() {
 		http.ServeFile(w, r, "./swagger/api/v1/resource.swagger.json")
 	})
 
+	// mount a path to expose the generated OpenAPI specification on disk
+	mux.HandleFunc("/swagger-ui/deadletter.swagger.json", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "./swagger/api/v1/deadletter.swagger.json")
+	})
+
+	// expose the counters of the status messages ingested and rejected
+	mux.Handle("/debug/vars", expvar.Handler())
+
 	// mount the Swagger UI that uses the OpenAPI specification path above
 	mux.Handle("/swagger-ui/", http.StripPrefix("/swagger-ui/", http.FileServer(http.Dir("./swagger-ui"))))
 
@@ -0,0 +1,104 @@
+// Command kubernetes-schemas bundles the schemas of the built-in Kubernetes kinds, taken from the
+// OpenAPI v3 documents published in the Kubernetes repository, into the gzipped OpenAPI v3
+// document embedded by the validation package.
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+)
+
+// kubernetesVersion is the release whose kinds are bundled, the one of k8s.io/api in go.mod.
+const kubernetesVersion = "v1.27.4"
+
+type document struct {
+	Components struct {
+		Schemas map[string]json.RawMessage `json:"schemas"`
+	} `json:"components"`
+}
+
+func main() {
+	output := flag.String("o", "kubernetes-schemas.json.gz", "file to write the bundle to")
+	flag.Parse()
+
+	dir, err := kubernetesSource()
+	if err != nil {
+		log.Fatalf("Failed to download Kubernetes %s: %v", kubernetesVersion, err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "api", "openapi-spec", "v3", "*.json"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	sort.Strings(files)
+
+	// the documents of the API groups share the schemas of the common types, e.g. ObjectMeta
+	schemas := map[string]json.RawMessage{}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			log.Fatal(err)
+		}
+		var d document
+		if err := json.Unmarshal(data, &d); err != nil {
+			log.Fatalf("Failed to read %s: %v", file, err)
+		}
+		for name, s := range d.Components.Schemas {
+			if _, ok := schemas[name]; !ok {
+				schemas[name] = s
+			}
+		}
+	}
+
+	bundle := map[string]interface{}{
+		"openapi":    "3.0.0",
+		"info":       map[string]string{"title": "Kubernetes", "version": kubernetesVersion},
+		"components": map[string]interface{}{"schemas": schemas},
+	}
+	data, err := json.Marshal(bundle)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var compressed bytes.Buffer
+	w, err := gzip.NewWriterLevel(&compressed, gzip.BestCompression)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if _, err := w.Write(data); err != nil {
+		log.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile(*output, compressed.Bytes(), 0o644); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Bundled %d schemas of Kubernetes %s in %s", len(schemas), kubernetesVersion, *output)
+}
+
+// kubernetesSource returns the directory of the Kubernetes sources in the module cache.
+func kubernetesSource() (string, error) {
+	cmd := exec.Command("go", "mod", "download", "-json", "k8s.io/kubernetes@"+kubernetesVersion)
+	// outside of the module, its go.mod and go.sum are left alone
+	cmd.Dir = os.TempDir()
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+
+	var module struct{ Dir string }
+	if err := json.Unmarshal(out, &module); err != nil {
+		return "", err
+	}
+	return module.Dir, nil
+}
@@ -2,6 +2,9 @@ package db
 
 import (
 	"context"
+	"errors"
+	"strconv"
+
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
@@ -11,23 +14,95 @@ import (
 
 const ConsumerTable = "Consumers"
 
-func PutConsumer(c *v1.Consumer) error {
-	jsonBytes, err := attributevalue.MarshalMap(c)
+func (s *DynamoDBStore) CreateConsumer(c *v1.Consumer) error {
+	item, err := attributevalue.MarshalMap(c)
 	if err != nil {
 		return err
 	}
 
-	_, err = dbClient.PutItem(
+	_, err = s.client.PutItem(
 		context.TODO(),
 		&dynamodb.PutItemInput{
-			TableName: aws.String(ConsumerTable),
-			Item:      jsonBytes,
+			TableName:           aws.String(ConsumerTable),
+			Item:                item,
+			ConditionExpression: aws.String("attribute_not_exists(Id)"),
 		})
 
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return &ErrorAlreadyExists{Kind: "Consumer"}
+	}
 	return err
 }
 
-func GetConsumer(consumerID string) (*v1.Consumer, error) {
+func (s *DynamoDBStore) SetConsumerDeletionTimestamp(consumerID string, expected, deletionTimestamp int64) (*v1.Consumer, error) {
+	result, err := s.client.UpdateItem(
+		context.TODO(),
+		&dynamodb.UpdateItemInput{
+			TableName: aws.String(ConsumerTable),
+			Key: map[string]types.AttributeValue{
+				"Id": &types.AttributeValueMemberS{Value: consumerID},
+			},
+			UpdateExpression:    aws.String("SET DeletionTimestamp = :deletionTimestamp"),
+			ConditionExpression: aws.String("DeletionTimestamp = :expected"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":deletionTimestamp": &types.AttributeValueMemberN{Value: strconv.FormatInt(deletionTimestamp, 10)},
+				":expected":          &types.AttributeValueMemberN{Value: strconv.FormatInt(expected, 10)},
+			},
+			ReturnValues: types.ReturnValueAllOld,
+			// tells a missing consumer, with no item, from one with another deletion timestamp
+			ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+		})
+
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		if conditionFailed.Item == nil {
+			return nil, &ErrorNotFound{Kind: "Consumer"}
+		}
+		return nil, &ErrorConflict{}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	old := &v1.Consumer{}
+	err = attributevalue.UnmarshalMap(result.Attributes, old)
+	return old, err
+}
+
+func (s *DynamoDBStore) UpdateConsumer(c *v1.Consumer) (*v1.Consumer, error) {
+	item, err := attributevalue.MarshalMap(c)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.client.UpdateItem(
+		context.TODO(),
+		&dynamodb.UpdateItemInput{
+			TableName: aws.String(ConsumerTable),
+			Key: map[string]types.AttributeValue{
+				"Id": &types.AttributeValueMemberS{Value: c.Id},
+			},
+			UpdateExpression:          aws.String("SET Labels = :labels"),
+			ConditionExpression:       aws.String("attribute_exists(Id)"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{":labels": item["Labels"]},
+			ReturnValues:              types.ReturnValueAllOld,
+		})
+
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return nil, &ErrorNotFound{Kind: "Consumer"}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	old := &v1.Consumer{}
+	err = attributevalue.UnmarshalMap(result.Attributes, old)
+	return old, err
+}
+
+func (s *DynamoDBStore) GetConsumer(consumerID string) (*v1.Consumer, error) {
 	getItemInput := &dynamodb.GetItemInput{
 		Key: map[string]types.AttributeValue{
 			"Id": &types.AttributeValueMemberS{Value: consumerID},
@@ -37,15 +112,77 @@ func GetConsumer(consumerID string) (*v1.Consumer, error) {
 
 	c := v1.Consumer{}
 
-	result, err := dbClient.GetItem(context.TODO(), getItemInput)
+	result, err := s.client.GetItem(context.TODO(), getItemInput)
 	if err != nil {
 		return nil, err
 	}
 
 	if result.Item == nil {
-		return nil, &ErrorNotFound{}
+		return nil, &ErrorNotFound{Kind: "Consumer"}
 	}
 
 	err = attributevalue.UnmarshalMap(result.Item, &c)
 	return &c, err
 }
+
+func (s *DynamoDBStore) DeleteConsumer(consumerID string) error {
+	_, err := s.client.DeleteItem(context.TODO(), &dynamodb.DeleteItemInput{
+		TableName: aws.String(ConsumerTable),
+		Key: map[string]types.AttributeValue{
+			"Id": &types.AttributeValueMemberS{Value: consumerID},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// the CRDs go away with the consumer
+	crds, err := s.ListCRDs(consumerID)
+	if err != nil {
+		return err
+	}
+	for _, c := range crds {
+		if err := s.DeleteCRD(c.ConsumerId, c.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListConsumers scans the Consumers table, DynamoDB cannot filter on the labels list server side.
+func (s *DynamoDBStore) ListConsumers(opts ConsumerListOptions) ([]*v1.Consumer, error) {
+	input := &dynamodb.ScanInput{
+		TableName: aws.String(ConsumerTable),
+	}
+	if opts.After != "" {
+		input.ExclusiveStartKey = map[string]types.AttributeValue{
+			"Id": &types.AttributeValueMemberS{Value: opts.After},
+		}
+	}
+
+	var consumers []*v1.Consumer
+	paginator := dynamodb.NewScanPaginator(s.client, input)
+	for paginator.HasMorePages() && (opts.Limit == 0 || len(consumers) < opts.Limit) {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return nil, err
+		}
+
+		var items []*v1.Consumer
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &items); err != nil {
+			return nil, err
+		}
+
+		for _, c := range items {
+			if !consumerMatches(opts.Selector, c) {
+				continue
+			}
+			consumers = append(consumers, c)
+			if len(consumers) == opts.Limit {
+				break
+			}
+		}
+	}
+
+	return consumers, nil
+}
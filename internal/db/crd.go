@@ -0,0 +1,104 @@
+package db
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const CRDTable = "ConsumerCRDs"
+
+// CRD is a CustomResourceDefinition installed on the cluster of a consumer.
+type CRD struct {
+	ConsumerId string
+	// Name of the CustomResourceDefinition, e.g. "crontabs.stable.example.com".
+	Name   string
+	Object unstructured.Unstructured
+}
+
+// DeepCopy returns a copy of the CRD that shares no state with c.
+func (c *CRD) DeepCopy() *CRD {
+	out := *c
+	out.Object = *c.Object.DeepCopy()
+	return &out
+}
+
+func (s *DynamoDBStore) PutCRD(c *CRD) error {
+	item, err := attributevalue.MarshalMap(c)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.PutItem(
+		context.TODO(),
+		&dynamodb.PutItemInput{
+			TableName: aws.String(CRDTable),
+			Item:      item,
+		})
+
+	return err
+}
+
+func (s *DynamoDBStore) GetCRD(consumerID, name string) (*CRD, error) {
+	result, err := s.client.GetItem(context.TODO(), &dynamodb.GetItemInput{
+		TableName: aws.String(CRDTable),
+		Key:       crdKey(consumerID, name),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Item == nil {
+		return nil, &ErrorNotFound{Kind: "CustomResourceDefinition"}
+	}
+
+	c := CRD{}
+	err = attributevalue.UnmarshalMap(result.Item, &c)
+	return &c, err
+}
+
+func (s *DynamoDBStore) ListCRDs(consumerID string) ([]*CRD, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(CRDTable),
+		KeyConditionExpression: aws.String("ConsumerId = :consumerId"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":consumerId": &types.AttributeValueMemberS{Value: consumerID},
+		},
+	}
+
+	var crds []*CRD
+	pages := dynamodb.NewQueryPaginator(s.client, input)
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(context.TODO())
+		if err != nil {
+			return nil, err
+		}
+
+		var items []*CRD
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &items); err != nil {
+			return nil, err
+		}
+		crds = append(crds, items...)
+	}
+
+	return crds, nil
+}
+
+func (s *DynamoDBStore) DeleteCRD(consumerID, name string) error {
+	_, err := s.client.DeleteItem(context.TODO(), &dynamodb.DeleteItemInput{
+		TableName: aws.String(CRDTable),
+		Key:       crdKey(consumerID, name),
+	})
+	return err
+}
+
+func crdKey(consumerID, name string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"ConsumerId": &types.AttributeValueMemberS{Value: consumerID},
+		"Name":       &types.AttributeValueMemberS{Value: name},
+	}
+}
@@ -11,22 +11,85 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 )
 
-var dbClient *dynamodb.Client
-
 const (
 	awsEndpoint        = "AWS_ENDPOINT"
 	awsAccessKeyID     = "AWS_ACCESS_KEY_ID"
 	awsSecretAccessKey = "AWS_SECRET_ACCESS_KEY"
 )
 
-type ErrorNotFound struct{}
+// ErrorNotFound is returned when the requested item is not stored.
+type ErrorNotFound struct {
+	// Kind of the item, e.g. "Consumer".
+	Kind string
+}
 
 func (e *ErrorNotFound) Error() string {
-	return fmt.Sprintf("Resource not found")
+	if e.Kind == "" {
+		return "Resource not found"
+	}
+	return fmt.Sprintf("%s not found", e.Kind)
+}
+
+// ErrorConflict is returned by conditional writes when the stored
+// resource is not at the expected generation anymore.
+type ErrorConflict struct{}
+
+func (e *ErrorConflict) Error() string {
+	return "Resource was modified concurrently"
+}
+
+// ErrorAlreadyExists is returned when creating an item whose ID is already taken.
+type ErrorAlreadyExists struct {
+	// Kind of the item, e.g. "Consumer".
+	Kind string
+}
+
+func (e *ErrorAlreadyExists) Error() string {
+	return fmt.Sprintf("%s already exists", e.Kind)
+}
+
+// ErrorStaleStatus is returned when a status is reported for an older generation
+// of the resource than the status already stored.
+type ErrorStaleStatus struct {
+	GenerationID         int64
+	ObservedGenerationID int64
+}
+
+func (e *ErrorStaleStatus) Error() string {
+	return fmt.Sprintf("status of generation %d is older than the status of generation %d", e.GenerationID, e.ObservedGenerationID)
+}
+
+// ErrorUnknownGeneration is returned when a status is reported for a generation the resource has not reached.
+type ErrorUnknownGeneration struct {
+	GenerationID         int64
+	ResourceGenerationID int64
+}
+
+func (e *ErrorUnknownGeneration) Error() string {
+	return fmt.Sprintf("status of generation %d is newer than the resource at generation %d", e.GenerationID, e.ResourceGenerationID)
+}
+
+// ErrorConsumerMismatch is returned when a consumer reports the status of a resource of another consumer.
+type ErrorConsumerMismatch struct {
+	ConsumerID         string
+	ResourceConsumerID string
 }
 
-func init() {
-	dbClient, _ = newClient()
+func (e *ErrorConsumerMismatch) Error() string {
+	return fmt.Sprintf("consumer %s reported the status of a resource of consumer %s", e.ConsumerID, e.ResourceConsumerID)
+}
+
+// DynamoDBStore is a Store backed by the Consumers and Resources DynamoDB tables.
+type DynamoDBStore struct {
+	client *dynamodb.Client
+}
+
+func NewDynamoDBStore() (*DynamoDBStore, error) {
+	client, err := newClient()
+	if err != nil {
+		return nil, err
+	}
+	return &DynamoDBStore{client: client}, nil
 }
 
 // newClient Creates a DynamoDB Client
@@ -59,7 +122,7 @@ func newClient() (*dynamodb.Client, error) {
 	)
 
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	return dynamodb.NewFromConfig(cfg), nil
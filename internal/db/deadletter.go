@@ -0,0 +1,121 @@
+package db
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const DeadLetterTable = "StatusDeadLetters"
+
+// DeadLetter is a status message that was rejected instead of being ingested.
+type DeadLetter struct {
+	// Id is a version 7 UUID, IDs sort in the order the messages were received.
+	Id string
+	// Topic the message was received on.
+	Topic string
+	// Consumer and resource named by the topic, empty when the topic is not a status topic.
+	ConsumerId string
+	ResourceId string
+	// Payload of the message, possibly truncated.
+	Payload []byte
+	// Reason the message was rejected for, e.g. "INVALID_PAYLOAD", and the detailed error.
+	Reason string
+	Error  string
+	// Unix timestamp at which the message was received.
+	ReceivedTimestamp int64
+	// Unix timestamp after which the dead letter is deleted.
+	ExpirationTimestamp int64
+}
+
+func (s *DynamoDBStore) PutDeadLetter(d *DeadLetter) error {
+	item, err := attributevalue.MarshalMap(d)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.PutItem(
+		context.TODO(),
+		&dynamodb.PutItemInput{
+			TableName: aws.String(DeadLetterTable),
+			Item:      item,
+		})
+
+	return err
+}
+
+func (s *DynamoDBStore) GetDeadLetter(id string) (*DeadLetter, error) {
+	result, err := s.client.GetItem(context.TODO(), &dynamodb.GetItemInput{
+		TableName: aws.String(DeadLetterTable),
+		Key:       deadLetterKey(id),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Item == nil {
+		return nil, &ErrorNotFound{Kind: "DeadLetter"}
+	}
+
+	d := DeadLetter{}
+	err = attributevalue.UnmarshalMap(result.Item, &d)
+	return &d, err
+}
+
+func (s *DynamoDBStore) ListDeadLetters(opts DeadLetterListOptions) ([]*DeadLetter, error) {
+	input := &dynamodb.ScanInput{
+		TableName: aws.String(DeadLetterTable),
+	}
+	if opts.After != "" {
+		input.ExclusiveStartKey = deadLetterKey(opts.After)
+	}
+
+	var deadLetters []*DeadLetter
+	paginator := dynamodb.NewScanPaginator(s.client, input)
+	for paginator.HasMorePages() && (opts.Limit == 0 || len(deadLetters) < opts.Limit) {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return nil, err
+		}
+
+		var items []*DeadLetter
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &items); err != nil {
+			return nil, err
+		}
+
+		for _, d := range items {
+			if !deadLetterMatches(opts, d) {
+				continue
+			}
+			deadLetters = append(deadLetters, d)
+			if len(deadLetters) == opts.Limit {
+				break
+			}
+		}
+	}
+
+	return deadLetters, nil
+}
+
+func (s *DynamoDBStore) DeleteDeadLetter(id string) error {
+	_, err := s.client.DeleteItem(context.TODO(), &dynamodb.DeleteItemInput{
+		TableName: aws.String(DeadLetterTable),
+		Key:       deadLetterKey(id),
+	})
+	return err
+}
+
+// DeleteExpiredDeadLetters leaves the expired dead letters to the time to live of the table,
+// set on ExpirationTimestamp.
+func (s *DynamoDBStore) DeleteExpiredDeadLetters(_ int64) error {
+	return nil
+}
+
+func deadLetterKey(id string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"Id": &types.AttributeValueMemberS{Value: id},
+	}
+}
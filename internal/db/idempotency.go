@@ -0,0 +1,134 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const IdempotencyTable = "IdempotencyRecords"
+
+// IdempotencyRecord remembers the result of a request sent with an idempotency key.
+type IdempotencyRecord struct {
+	// Idempotency key of the request, scoped by the caller.
+	IdempotencyKey string
+	// Hash of the request, retries must send the same request.
+	RequestHash string
+	// Serialized response to the request, empty while the request is being processed.
+	Response []byte
+	// Unix timestamp after which the record is ignored.
+	ExpirationTimestamp int64
+}
+
+func (s *DynamoDBStore) CreateIdempotencyRecord(r *IdempotencyRecord, now int64) error {
+	item, err := attributevalue.MarshalMap(r)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.PutItem(
+		context.TODO(),
+		&dynamodb.PutItemInput{
+			TableName: aws.String(IdempotencyTable),
+			Item:      item,
+			// the time to live deletes the expired records late, take them over meanwhile
+			ConditionExpression: aws.String("attribute_not_exists(IdempotencyKey) OR ExpirationTimestamp <= :now"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":now": &types.AttributeValueMemberN{Value: strconv.FormatInt(now, 10)},
+			},
+		})
+
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return &ErrorAlreadyExists{Kind: "IdempotencyRecord"}
+	}
+	return err
+}
+
+func (s *DynamoDBStore) UpdateIdempotencyRecord(r *IdempotencyRecord, leaseExpiration int64) error {
+	item, err := attributevalue.MarshalMap(r)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.PutItem(
+		context.TODO(),
+		&dynamodb.PutItemInput{
+			TableName:                           aws.String(IdempotencyTable),
+			Item:                                item,
+			ConditionExpression:                 aws.String(idempotencyClaimCondition),
+			ExpressionAttributeValues:           idempotencyClaimValues(r.RequestHash, leaseExpiration),
+			ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+		})
+
+	return idempotencyClaimError(err)
+}
+
+func (s *DynamoDBStore) GetIdempotencyRecord(key string) (*IdempotencyRecord, error) {
+	result, err := s.client.GetItem(context.TODO(), &dynamodb.GetItemInput{
+		TableName: aws.String(IdempotencyTable),
+		Key:       idempotencyKey(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Item == nil {
+		return nil, &ErrorNotFound{Kind: "IdempotencyRecord"}
+	}
+
+	r := IdempotencyRecord{}
+	err = attributevalue.UnmarshalMap(result.Item, &r)
+	return &r, err
+}
+
+func (s *DynamoDBStore) DeleteIdempotencyRecord(r *IdempotencyRecord) error {
+	_, err := s.client.DeleteItem(context.TODO(), &dynamodb.DeleteItemInput{
+		TableName:                           aws.String(IdempotencyTable),
+		Key:                                 idempotencyKey(r.IdempotencyKey),
+		ConditionExpression:                 aws.String(idempotencyClaimCondition),
+		ExpressionAttributeValues:           idempotencyClaimValues(r.RequestHash, r.ExpirationTimestamp),
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	})
+	return idempotencyClaimError(err)
+}
+
+// idempotencyClaimCondition holds while a record is the claim of the request of :requestHash expiring at :lease.
+const idempotencyClaimCondition = "RequestHash = :requestHash AND ExpirationTimestamp = :lease"
+
+func idempotencyClaimValues(requestHash string, leaseExpiration int64) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		":requestHash": &types.AttributeValueMemberS{Value: requestHash},
+		":lease":       &types.AttributeValueMemberN{Value: strconv.FormatInt(leaseExpiration, 10)},
+	}
+}
+
+// idempotencyClaimError returns the error of a write conditioned on the claim of a record,
+// the record is returned when the condition fails.
+func idempotencyClaimError(err error) error {
+	var conditionFailed *types.ConditionalCheckFailedException
+	if !errors.As(err, &conditionFailed) {
+		return err
+	}
+	if conditionFailed.Item == nil {
+		return &ErrorNotFound{Kind: "IdempotencyRecord"}
+	}
+	return &ErrorConflict{}
+}
+
+// DeleteExpiredIdempotencyRecords leaves the expired records to the time to live of the table,
+// set on ExpirationTimestamp.
+func (s *DynamoDBStore) DeleteExpiredIdempotencyRecords(_ int64) error {
+	return nil
+}
+
+func idempotencyKey(key string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"IdempotencyKey": &types.AttributeValueMemberS{Value: key},
+	}
+}
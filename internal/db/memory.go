@@ -0,0 +1,445 @@
+package db
+
+import (
+	"sort"
+	"sync"
+
+	v1 "github.com/kube-orchestra/maestro/proto/api/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// MemoryStore is a Store that keeps everything in process memory.
+// Its content is lost when the process exits.
+type MemoryStore struct {
+	mu        sync.RWMutex
+	consumers map[string]*v1.Consumer
+	resources map[string]*Resource
+	revisions map[string]map[int64]*Revision
+	crds      map[string]map[string]*CRD
+	// idempotency records by key
+	idempotencyRecords map[string]*IdempotencyRecord
+	deadLetters        map[string]*DeadLetter
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		consumers: map[string]*v1.Consumer{},
+		resources: map[string]*Resource{},
+		revisions: map[string]map[int64]*Revision{},
+		crds:      map[string]map[string]*CRD{},
+
+		idempotencyRecords: map[string]*IdempotencyRecord{},
+		deadLetters:        map[string]*DeadLetter{},
+	}
+}
+
+func (s *MemoryStore) CreateConsumer(c *v1.Consumer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.consumers[c.Id]; ok {
+		return &ErrorAlreadyExists{Kind: "Consumer"}
+	}
+	s.consumers[c.Id] = proto.Clone(c).(*v1.Consumer)
+	return nil
+}
+
+func (s *MemoryStore) SetConsumerDeletionTimestamp(consumerID string, expected, deletionTimestamp int64) (*v1.Consumer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, ok := s.consumers[consumerID]
+	if !ok {
+		return nil, &ErrorNotFound{Kind: "Consumer"}
+	}
+	if old.DeletionTimestamp != expected {
+		return nil, &ErrorConflict{}
+	}
+
+	updated := proto.Clone(old).(*v1.Consumer)
+	updated.DeletionTimestamp = deletionTimestamp
+	s.consumers[consumerID] = updated
+	return proto.Clone(old).(*v1.Consumer), nil
+}
+
+func (s *MemoryStore) UpdateConsumer(c *v1.Consumer) (*v1.Consumer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, ok := s.consumers[c.Id]
+	if !ok {
+		return nil, &ErrorNotFound{Kind: "Consumer"}
+	}
+
+	updated := proto.Clone(old).(*v1.Consumer)
+	updated.Labels = proto.Clone(c).(*v1.Consumer).Labels
+	s.consumers[c.Id] = updated
+	return proto.Clone(old).(*v1.Consumer), nil
+}
+
+func (s *MemoryStore) GetConsumer(consumerID string) (*v1.Consumer, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	c, ok := s.consumers[consumerID]
+	if !ok {
+		return nil, &ErrorNotFound{Kind: "Consumer"}
+	}
+	return proto.Clone(c).(*v1.Consumer), nil
+}
+
+func (s *MemoryStore) ListConsumers(opts ConsumerListOptions) ([]*v1.Consumer, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := make([]string, 0, len(s.consumers))
+	for id := range s.consumers {
+		if id > opts.After {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	var consumers []*v1.Consumer
+	for _, id := range ids {
+		if opts.Limit > 0 && len(consumers) == opts.Limit {
+			break
+		}
+		if c := s.consumers[id]; consumerMatches(opts.Selector, c) {
+			consumers = append(consumers, proto.Clone(c).(*v1.Consumer))
+		}
+	}
+	return consumers, nil
+}
+
+func (s *MemoryStore) DeleteConsumer(consumerID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.consumers, consumerID)
+	delete(s.crds, consumerID)
+	return nil
+}
+
+func (s *MemoryStore) PutCRD(c *CRD) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	crds, ok := s.crds[c.ConsumerId]
+	if !ok {
+		crds = map[string]*CRD{}
+		s.crds[c.ConsumerId] = crds
+	}
+	crds[c.Name] = c.DeepCopy()
+	return nil
+}
+
+func (s *MemoryStore) GetCRD(consumerID, name string) (*CRD, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	c, ok := s.crds[consumerID][name]
+	if !ok {
+		return nil, &ErrorNotFound{Kind: "CustomResourceDefinition"}
+	}
+	return c.DeepCopy(), nil
+}
+
+func (s *MemoryStore) ListCRDs(consumerID string) ([]*CRD, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	crds := make([]*CRD, 0, len(s.crds[consumerID]))
+	for _, c := range s.crds[consumerID] {
+		crds = append(crds, c.DeepCopy())
+	}
+	sort.Slice(crds, func(i, j int) bool {
+		return crds[i].Name < crds[j].Name
+	})
+	return crds, nil
+}
+
+func (s *MemoryStore) DeleteCRD(consumerID, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.crds[consumerID], name)
+	return nil
+}
+
+func (s *MemoryStore) CreateResource(r *Resource) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.resources[r.Id]; ok {
+		return &ErrorAlreadyExists{Kind: "Resource"}
+	}
+	s.resources[r.Id] = r.DeepCopy()
+	return nil
+}
+
+func (s *MemoryStore) UpdateResource(r *Resource, expectedGenerationID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.resources[r.Id]
+	if !ok {
+		return &ErrorNotFound{Kind: "Resource"}
+	}
+	if stored.ResourceGenerationID != expectedGenerationID {
+		return &ErrorConflict{}
+	}
+
+	updated := r.DeepCopy()
+	updated.Status = stored.Status
+	updated.ObservedGenerationID = stored.ObservedGenerationID
+	s.resources[r.Id] = updated
+	return nil
+}
+
+func (s *MemoryStore) GetResource(resourceID string) (*Resource, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	r, ok := s.resources[resourceID]
+	if !ok {
+		return nil, &ErrorNotFound{Kind: "Resource"}
+	}
+	return r.DeepCopy(), nil
+}
+
+func (s *MemoryStore) DeleteResource(resourceID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.resources, resourceID)
+	delete(s.revisions, resourceID)
+	return nil
+}
+
+func (s *MemoryStore) ListResources(opts ResourceListOptions) ([]*Resource, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := make([]string, 0, len(s.resources))
+	for id := range s.resources {
+		if id > opts.After {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	var resources []*Resource
+	for _, id := range ids {
+		if opts.Limit > 0 && len(resources) == opts.Limit {
+			break
+		}
+		if r := s.resources[id]; resourceMatches(opts, r) {
+			resources = append(resources, r.DeepCopy())
+		}
+	}
+	return resources, nil
+}
+
+func (s *MemoryStore) SetStatusResource(consumerID, resourceID string, status *StatusMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.resources[resourceID]
+	if !ok {
+		return &ErrorNotFound{Kind: "Resource"}
+	}
+	if err := checkStatus(r, consumerID, status); err != nil {
+		return err
+	}
+	r.Status = *status.DeepCopy()
+	r.ObservedGenerationID = status.ResourceGenerationID
+	return nil
+}
+
+func (s *MemoryStore) PutRevision(r *Revision) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	revisions, ok := s.revisions[r.ResourceId]
+	if !ok {
+		revisions = map[int64]*Revision{}
+		s.revisions[r.ResourceId] = revisions
+	}
+	revisions[r.ResourceGenerationID] = r.DeepCopy()
+	return nil
+}
+
+func (s *MemoryStore) GetRevision(resourceID string, generationID int64) (*Revision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	r, ok := s.revisions[resourceID][generationID]
+	if !ok {
+		return nil, &ErrorNotFound{Kind: "Revision"}
+	}
+	return r.DeepCopy(), nil
+}
+
+func (s *MemoryStore) ListRevisions(resourceID string) ([]*Revision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	revisions := make([]*Revision, 0, len(s.revisions[resourceID]))
+	for _, r := range s.revisions[resourceID] {
+		revisions = append(revisions, r.DeepCopy())
+	}
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].ResourceGenerationID > revisions[j].ResourceGenerationID
+	})
+	return revisions, nil
+}
+
+func (s *MemoryStore) DeleteRevisions(resourceID string, beforeGenerationID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for generationID := range s.revisions[resourceID] {
+		if generationID < beforeGenerationID {
+			delete(s.revisions[resourceID], generationID)
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) CreateIdempotencyRecord(r *IdempotencyRecord, now int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.idempotencyRecords[r.IdempotencyKey]; ok && existing.ExpirationTimestamp > now {
+		return &ErrorAlreadyExists{Kind: "IdempotencyRecord"}
+	}
+	stored := *r
+	s.idempotencyRecords[r.IdempotencyKey] = &stored
+	return nil
+}
+
+func (s *MemoryStore) UpdateIdempotencyRecord(r *IdempotencyRecord, leaseExpiration int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkIdempotencyClaim(r.IdempotencyKey, r.RequestHash, leaseExpiration); err != nil {
+		return err
+	}
+	stored := *r
+	s.idempotencyRecords[r.IdempotencyKey] = &stored
+	return nil
+}
+
+func (s *MemoryStore) GetIdempotencyRecord(key string) (*IdempotencyRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	r, ok := s.idempotencyRecords[key]
+	if !ok {
+		return nil, &ErrorNotFound{Kind: "IdempotencyRecord"}
+	}
+	out := *r
+	return &out, nil
+}
+
+func (s *MemoryStore) DeleteIdempotencyRecord(r *IdempotencyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkIdempotencyClaim(r.IdempotencyKey, r.RequestHash, r.ExpirationTimestamp); err != nil {
+		return err
+	}
+	delete(s.idempotencyRecords, r.IdempotencyKey)
+	return nil
+}
+
+// checkIdempotencyClaim checks that the record of key is the claim of the request of requestHash expiring at leaseExpiration.
+func (s *MemoryStore) checkIdempotencyClaim(key, requestHash string, leaseExpiration int64) error {
+	existing, ok := s.idempotencyRecords[key]
+	if !ok {
+		return &ErrorNotFound{Kind: "IdempotencyRecord"}
+	}
+	if existing.RequestHash != requestHash || existing.ExpirationTimestamp != leaseExpiration {
+		return &ErrorConflict{}
+	}
+	return nil
+}
+
+func (s *MemoryStore) DeleteExpiredIdempotencyRecords(now int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, r := range s.idempotencyRecords {
+		if r.ExpirationTimestamp <= now {
+			delete(s.idempotencyRecords, key)
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) PutDeadLetter(d *DeadLetter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *d
+	s.deadLetters[d.Id] = &stored
+	return nil
+}
+
+func (s *MemoryStore) GetDeadLetter(id string) (*DeadLetter, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	d, ok := s.deadLetters[id]
+	if !ok {
+		return nil, &ErrorNotFound{Kind: "DeadLetter"}
+	}
+	out := *d
+	return &out, nil
+}
+
+func (s *MemoryStore) ListDeadLetters(opts DeadLetterListOptions) ([]*DeadLetter, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := make([]string, 0, len(s.deadLetters))
+	for id := range s.deadLetters {
+		if id > opts.After {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	var deadLetters []*DeadLetter
+	for _, id := range ids {
+		if opts.Limit > 0 && len(deadLetters) == opts.Limit {
+			break
+		}
+		if d := s.deadLetters[id]; deadLetterMatches(opts, d) {
+			out := *d
+			deadLetters = append(deadLetters, &out)
+		}
+	}
+	return deadLetters, nil
+}
+
+func (s *MemoryStore) DeleteDeadLetter(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.deadLetters, id)
+	return nil
+}
+
+func (s *MemoryStore) DeleteExpiredDeadLetters(now int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, d := range s.deadLetters {
+		if d.ExpirationTimestamp <= now {
+			delete(s.deadLetters, id)
+		}
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package db
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 type ResourceMessage struct {
@@ -13,6 +14,11 @@ type ResourceMessage struct {
 
 	// Kubernetes Manifest to apply on the target.
 	Content *unstructured.Unstructured `json:"content"`
+
+	// Unix Timestamp (UTC) at which deletion was requested.
+	// When set, the object described by Content MUST be removed from the target
+	// and the removal reported with the Deleted condition.
+	DeletionTimestamp int64 `json:"deletionTimestamp,omitempty"`
 }
 
 type StatusMessage struct {
@@ -23,6 +29,21 @@ type StatusMessage struct {
 	ContentStatus map[string]interface{} `json:"contentStatus"`
 }
 
+// DeepCopy returns a copy of the status message that shares no state with m.
+func (m *StatusMessage) DeepCopy() *StatusMessage {
+	out := *m
+	if m.ReconcileStatus.Conditions != nil {
+		out.ReconcileStatus.Conditions = make([]metav1.Condition, len(m.ReconcileStatus.Conditions))
+		for i := range m.ReconcileStatus.Conditions {
+			m.ReconcileStatus.Conditions[i].DeepCopyInto(&out.ReconcileStatus.Conditions[i])
+		}
+	}
+	if m.ContentStatus != nil {
+		out.ContentStatus = runtime.DeepCopyJSON(m.ContentStatus)
+	}
+	return &out
+}
+
 const (
 	// Reconciled condition tracks the state of the reconcile operation.
 	// "True" indicates that the object has been successfully applied.
@@ -0,0 +1 @@
+CREATE INDEX consumers_labels_idx ON consumers USING GIN (labels);
@@ -0,0 +1,8 @@
+CREATE TABLE resource_revisions (
+    resource_id        TEXT NOT NULL,
+    generation         BIGINT NOT NULL,
+    object             JSONB NOT NULL,
+    author             TEXT NOT NULL DEFAULT '',
+    creation_timestamp BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (resource_id, generation)
+);
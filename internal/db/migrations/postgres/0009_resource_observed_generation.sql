@@ -0,0 +1,3 @@
+ALTER TABLE resources ADD COLUMN observed_generation BIGINT NOT NULL DEFAULT 0;
+
+UPDATE resources SET observed_generation = COALESCE((status->>'resourceGenerationID')::BIGINT, 0);
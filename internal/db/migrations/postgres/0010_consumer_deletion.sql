@@ -0,0 +1 @@
+ALTER TABLE consumers ADD COLUMN deletion_timestamp BIGINT NOT NULL DEFAULT 0;
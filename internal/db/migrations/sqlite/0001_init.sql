@@ -0,0 +1,14 @@
+CREATE TABLE consumers (
+    id     TEXT PRIMARY KEY,
+    labels TEXT NOT NULL DEFAULT '{}'
+);
+
+CREATE TABLE resources (
+    id          TEXT PRIMARY KEY,
+    consumer_id TEXT NOT NULL,
+    generation  INTEGER NOT NULL,
+    object      TEXT NOT NULL,
+    status      TEXT NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX resources_consumer_id_idx ON resources (consumer_id);
@@ -0,0 +1 @@
+ALTER TABLE resources ADD COLUMN deletion_timestamp INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,12 @@
+ALTER TABLE resources ADD COLUMN api_version TEXT NOT NULL DEFAULT '';
+ALTER TABLE resources ADD COLUMN kind TEXT NOT NULL DEFAULT '';
+ALTER TABLE resources ADD COLUMN namespace TEXT NOT NULL DEFAULT '';
+ALTER TABLE resources ADD COLUMN name TEXT NOT NULL DEFAULT '';
+
+UPDATE resources SET
+    api_version = COALESCE(object ->> '$.apiVersion', ''),
+    kind        = COALESCE(object ->> '$.kind', ''),
+    namespace   = COALESCE(object ->> '$.metadata.namespace', ''),
+    name        = COALESCE(object ->> '$.metadata.name', '');
+
+CREATE INDEX resources_kind_namespace_name_idx ON resources (kind, namespace, name);
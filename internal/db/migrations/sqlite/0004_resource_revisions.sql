@@ -0,0 +1,8 @@
+CREATE TABLE resource_revisions (
+    resource_id        TEXT NOT NULL,
+    generation         INTEGER NOT NULL,
+    object             TEXT NOT NULL,
+    author             TEXT NOT NULL DEFAULT '',
+    creation_timestamp INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (resource_id, generation)
+);
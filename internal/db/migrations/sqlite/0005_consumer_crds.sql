@@ -0,0 +1,6 @@
+CREATE TABLE consumer_crds (
+    consumer_id TEXT NOT NULL,
+    name        TEXT NOT NULL,
+    object      TEXT NOT NULL,
+    PRIMARY KEY (consumer_id, name)
+);
@@ -0,0 +1,8 @@
+CREATE TABLE idempotency_records (
+    idempotency_key      TEXT PRIMARY KEY,
+    request_hash         TEXT NOT NULL,
+    response             BLOB,
+    expiration_timestamp INTEGER NOT NULL
+);
+
+CREATE INDEX idempotency_records_expiration_idx ON idempotency_records (expiration_timestamp);
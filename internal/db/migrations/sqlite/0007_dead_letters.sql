@@ -0,0 +1,13 @@
+CREATE TABLE dead_letters (
+    id                   TEXT PRIMARY KEY,
+    topic                TEXT NOT NULL,
+    consumer_id          TEXT NOT NULL,
+    resource_id          TEXT NOT NULL,
+    payload              BLOB,
+    reason               TEXT NOT NULL,
+    error                TEXT NOT NULL,
+    received_timestamp   INTEGER NOT NULL,
+    expiration_timestamp INTEGER NOT NULL
+);
+
+CREATE INDEX dead_letters_expiration_idx ON dead_letters (expiration_timestamp);
@@ -0,0 +1,3 @@
+ALTER TABLE resources ADD COLUMN observed_generation INTEGER NOT NULL DEFAULT 0;
+
+UPDATE resources SET observed_generation = COALESCE(json_extract(status, '$.resourceGenerationID'), 0);
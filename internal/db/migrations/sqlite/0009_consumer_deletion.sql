@@ -0,0 +1 @@
+ALTER TABLE consumers ADD COLUMN deletion_timestamp INTEGER NOT NULL DEFAULT 0;
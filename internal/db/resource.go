@@ -3,6 +3,9 @@ package db
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
@@ -13,31 +16,94 @@ import (
 
 const ResourceTable = "Resources"
 
+// ResourceConsumerIndex is the global secondary index of the Resources table on ConsumerId.
+const ResourceConsumerIndex = "ConsumerIdIndex"
+
 type Resource struct {
 	Id                   string
 	ConsumerId           string
 	ResourceGenerationID int64
 	Object               unstructured.Unstructured
 	Status               StatusMessage
+	// Generation the status was reported for, zero until a status is reported.
+	// Statuses of older generations are stale and not stored.
+	ObservedGenerationID int64
+	// Unix timestamp at which deletion was requested, zero otherwise.
+	DeletionTimestamp int64
+}
+
+// DeepCopy returns a copy of the resource that shares no state with r.
+func (r *Resource) DeepCopy() *Resource {
+	out := *r
+	out.Object = *r.Object.DeepCopy()
+	out.Status = *r.Status.DeepCopy()
+	return &out
 }
 
-func PutResource(r *Resource) error {
-	jsonBytes, err := attributevalue.MarshalMap(r)
+func (s *DynamoDBStore) CreateResource(r *Resource) error {
+	item, err := attributevalue.MarshalMap(r)
 	if err != nil {
 		return err
 	}
 
-	_, err = dbClient.PutItem(
+	_, err = s.client.PutItem(
 		context.TODO(),
 		&dynamodb.PutItemInput{
+			TableName:           aws.String(ResourceTable),
+			Item:                item,
+			ConditionExpression: aws.String("attribute_not_exists(Id)"),
+		})
+
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return &ErrorAlreadyExists{Kind: "Resource"}
+	}
+	return err
+}
+
+func (s *DynamoDBStore) UpdateResource(r *Resource, expectedGenerationID int64) error {
+	item, err := attributevalue.MarshalMap(r)
+	if err != nil {
+		return err
+	}
+
+	// the status and observed generation are left to SetStatusResource
+	_, err = s.client.UpdateItem(
+		context.TODO(),
+		&dynamodb.UpdateItemInput{
 			TableName: aws.String(ResourceTable),
-			Item:      jsonBytes,
+			Key: map[string]types.AttributeValue{
+				"Id": &types.AttributeValueMemberS{Value: r.Id},
+			},
+			UpdateExpression: aws.String("SET ConsumerId = :consumerId, ResourceGenerationID = :generationID, " +
+				"#object = :object, DeletionTimestamp = :deletionTimestamp"),
+			ConditionExpression: aws.String("ResourceGenerationID = :expectedGenerationID"),
+			ExpressionAttributeNames: map[string]string{
+				// OBJECT is a reserved word
+				"#object": "Object",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":consumerId":           item["ConsumerId"],
+				":generationID":         item["ResourceGenerationID"],
+				":object":               item["Object"],
+				":deletionTimestamp":    item["DeletionTimestamp"],
+				":expectedGenerationID": &types.AttributeValueMemberN{Value: strconv.FormatInt(expectedGenerationID, 10)},
+			},
+			// tells a missing resource, with no item, from one at another generation
+			ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
 		})
 
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		if conditionFailed.Item == nil {
+			return &ErrorNotFound{Kind: "Resource"}
+		}
+		return &ErrorConflict{}
+	}
 	return err
 }
 
-func GetResource(resourceID string) (*Resource, error) {
+func (s *DynamoDBStore) GetResource(resourceID string) (*Resource, error) {
 	getItemInput := &dynamodb.GetItemInput{
 		Key: map[string]types.AttributeValue{
 			"Id": &types.AttributeValueMemberS{Value: resourceID},
@@ -47,36 +113,147 @@ func GetResource(resourceID string) (*Resource, error) {
 
 	r := Resource{}
 
-	result, err := dbClient.GetItem(context.TODO(), getItemInput)
+	result, err := s.client.GetItem(context.TODO(), getItemInput)
 	if err != nil {
 		return nil, err
 	}
 
 	if result.Item == nil {
-		return nil, &ErrorNotFound{}
+		return nil, &ErrorNotFound{Kind: "Resource"}
 	}
 
 	err = attributevalue.UnmarshalMap(result.Item, &r)
 	return &r, err
 }
 
-func SetStatusResource(resourceID string, statusData []byte) error {
-	var status map[string]interface{}
-	if err := json.Unmarshal(statusData, &status); err != nil {
+func (s *DynamoDBStore) DeleteResource(resourceID string) error {
+	_, err := s.client.DeleteItem(context.TODO(), &dynamodb.DeleteItemInput{
+		TableName: aws.String(ResourceTable),
+		Key: map[string]types.AttributeValue{
+			"Id": &types.AttributeValueMemberS{Value: resourceID},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// the history goes away with the resource
+	return s.DeleteRevisions(resourceID, math.MaxInt64)
+}
+
+// ListResources queries the ConsumerId index when a consumer is given and scans the table otherwise.
+// The other filters are applied on the returned items: a DynamoDB filter expression
+// would consume the same read capacity.
+func (s *DynamoDBStore) ListResources(opts ResourceListOptions) ([]*Resource, error) {
+	var pages interface {
+		HasMorePages() bool
+		NextPage(context.Context, ...func(*dynamodb.Options)) ([]map[string]types.AttributeValue, error)
+	}
+
+	if opts.ConsumerId != "" {
+		input := &dynamodb.QueryInput{
+			TableName:              aws.String(ResourceTable),
+			IndexName:              aws.String(ResourceConsumerIndex),
+			KeyConditionExpression: aws.String("ConsumerId = :consumerId"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":consumerId": &types.AttributeValueMemberS{Value: opts.ConsumerId},
+			},
+		}
+		if opts.After != "" {
+			input.ExclusiveStartKey = map[string]types.AttributeValue{
+				"Id":         &types.AttributeValueMemberS{Value: opts.After},
+				"ConsumerId": &types.AttributeValueMemberS{Value: opts.ConsumerId},
+			}
+		}
+		pages = &queryPages{dynamodb.NewQueryPaginator(s.client, input)}
+	} else {
+		input := &dynamodb.ScanInput{
+			TableName: aws.String(ResourceTable),
+		}
+		if opts.After != "" {
+			input.ExclusiveStartKey = map[string]types.AttributeValue{
+				"Id": &types.AttributeValueMemberS{Value: opts.After},
+			}
+		}
+		pages = &scanPages{dynamodb.NewScanPaginator(s.client, input)}
+	}
+
+	var resources []*Resource
+	for pages.HasMorePages() && (opts.Limit == 0 || len(resources) < opts.Limit) {
+		page, err := pages.NextPage(context.TODO())
+		if err != nil {
+			return nil, err
+		}
+
+		var items []*Resource
+		if err := attributevalue.UnmarshalListOfMaps(page, &items); err != nil {
+			return nil, err
+		}
+
+		for _, r := range items {
+			if !resourceMatches(opts, r) {
+				continue
+			}
+			resources = append(resources, r)
+			if len(resources) == opts.Limit {
+				break
+			}
+		}
+	}
+
+	return resources, nil
+}
+
+// queryPages and scanPages give query and scan paginators the same shape.
+type queryPages struct {
+	*dynamodb.QueryPaginator
+}
+
+func (p *queryPages) NextPage(ctx context.Context, optFns ...func(*dynamodb.Options)) ([]map[string]types.AttributeValue, error) {
+	page, err := p.QueryPaginator.NextPage(ctx, optFns...)
+	if err != nil {
+		return nil, err
+	}
+	return page.Items, nil
+}
+
+type scanPages struct {
+	*dynamodb.ScanPaginator
+}
+
+func (p *scanPages) NextPage(ctx context.Context, optFns ...func(*dynamodb.Options)) ([]map[string]types.AttributeValue, error) {
+	page, err := p.ScanPaginator.NextPage(ctx, optFns...)
+	if err != nil {
+		return nil, err
+	}
+	return page.Items, nil
+}
+
+func (s *DynamoDBStore) SetStatusResource(consumerID, resourceID string, status *StatusMessage) error {
+	statusData, err := json.Marshal(status)
+	if err != nil {
 		return err
 	}
 
-	statusAV, err := attributevalue.MarshalMap(status)
+	var statusMap map[string]interface{}
+	if err := json.Unmarshal(statusData, &statusMap); err != nil {
+		return err
+	}
+
+	statusAV, err := attributevalue.MarshalMap(statusMap)
 	if err != nil {
 		return err
 	}
 
+	// resources stored before ObservedGenerationID was introduced accept any generation
 	input := &dynamodb.UpdateItemInput{
 		TableName: aws.String(ResourceTable),
 		Key: map[string]types.AttributeValue{
 			"Id": &types.AttributeValueMemberS{Value: resourceID},
 		},
-		UpdateExpression: aws.String("SET #statusField = :statusValue"),
+		UpdateExpression: aws.String("SET #statusField = :statusValue, ObservedGenerationID = :generationID"),
+		ConditionExpression: aws.String("ConsumerId = :consumerId AND ResourceGenerationID >= :generationID AND " +
+			"(attribute_not_exists(ObservedGenerationID) OR ObservedGenerationID <= :generationID)"),
 		ExpressionAttributeNames: map[string]string{
 			"#statusField": "Status",
 		},
@@ -84,9 +261,25 @@ func SetStatusResource(resourceID string, statusData []byte) error {
 			":statusValue": &types.AttributeValueMemberM{
 				Value: statusAV,
 			},
+			":generationID": &types.AttributeValueMemberN{Value: strconv.FormatInt(status.ResourceGenerationID, 10)},
+			":consumerId":   &types.AttributeValueMemberS{Value: consumerID},
 		},
 	}
 
-	_, err = dbClient.UpdateItem(context.TODO(), input)
-	return err
+	_, err = s.client.UpdateItem(context.TODO(), input)
+
+	var conditionFailed *types.ConditionalCheckFailedException
+	if !errors.As(err, &conditionFailed) {
+		return err
+	}
+
+	// tell why the status was not stored
+	r, err := s.GetResource(resourceID)
+	if err != nil {
+		return err
+	}
+	if err := checkStatus(r, consumerID, status); err != nil {
+		return err
+	}
+	return &ErrorConflict{}
 }
@@ -0,0 +1,130 @@
+package db
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const RevisionTable = "ResourceRevisions"
+
+// Revision is the object of a resource as written at one of its generations.
+type Revision struct {
+	ResourceId           string
+	ResourceGenerationID int64
+	Object               unstructured.Unstructured
+	// Field manager that wrote the generation.
+	Author string
+	// Unix timestamp at which the generation was written.
+	CreationTimestamp int64
+}
+
+// DeepCopy returns a copy of the revision that shares no state with r.
+func (r *Revision) DeepCopy() *Revision {
+	out := *r
+	out.Object = *r.Object.DeepCopy()
+	return &out
+}
+
+func (s *DynamoDBStore) PutRevision(r *Revision) error {
+	item, err := attributevalue.MarshalMap(r)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.PutItem(
+		context.TODO(),
+		&dynamodb.PutItemInput{
+			TableName: aws.String(RevisionTable),
+			Item:      item,
+		})
+
+	return err
+}
+
+func (s *DynamoDBStore) GetRevision(resourceID string, generationID int64) (*Revision, error) {
+	result, err := s.client.GetItem(context.TODO(), &dynamodb.GetItemInput{
+		TableName: aws.String(RevisionTable),
+		Key:       revisionKey(resourceID, generationID),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Item == nil {
+		return nil, &ErrorNotFound{Kind: "Revision"}
+	}
+
+	r := Revision{}
+	err = attributevalue.UnmarshalMap(result.Item, &r)
+	return &r, err
+}
+
+func (s *DynamoDBStore) ListRevisions(resourceID string) ([]*Revision, error) {
+	return s.queryRevisions(resourceID, 0)
+}
+
+// DeleteRevisions deletes the revisions one by one, there are few of them past the history limit.
+func (s *DynamoDBStore) DeleteRevisions(resourceID string, beforeGenerationID int64) error {
+	revisions, err := s.queryRevisions(resourceID, beforeGenerationID)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range revisions {
+		_, err := s.client.DeleteItem(context.TODO(), &dynamodb.DeleteItemInput{
+			TableName: aws.String(RevisionTable),
+			Key:       revisionKey(r.ResourceId, r.ResourceGenerationID),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// queryRevisions returns the revisions of a resource before beforeGenerationID,
+// or all of them when it is zero, the latest first.
+func (s *DynamoDBStore) queryRevisions(resourceID string, beforeGenerationID int64) ([]*Revision, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(RevisionTable),
+		KeyConditionExpression: aws.String("ResourceId = :resourceId"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":resourceId": &types.AttributeValueMemberS{Value: resourceID},
+		},
+		ScanIndexForward: aws.Bool(false),
+	}
+	if beforeGenerationID != 0 {
+		input.KeyConditionExpression = aws.String("ResourceId = :resourceId AND ResourceGenerationID < :before")
+		input.ExpressionAttributeValues[":before"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(beforeGenerationID, 10)}
+	}
+
+	var revisions []*Revision
+	pages := dynamodb.NewQueryPaginator(s.client, input)
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(context.TODO())
+		if err != nil {
+			return nil, err
+		}
+
+		var items []*Revision
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &items); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, items...)
+	}
+
+	return revisions, nil
+}
+
+func revisionKey(resourceID string, generationID int64) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"ResourceId":           &types.AttributeValueMemberS{Value: resourceID},
+		"ResourceGenerationID": &types.AttributeValueMemberN{Value: strconv.FormatInt(generationID, 10)},
+	}
+}
@@ -0,0 +1,863 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+
+	v1 "github.com/kube-orchestra/maestro/proto/api/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+)
+
+// SQLStore is a Store backed by a relational database.
+// Manifests, labels and status are kept in JSON columns.
+type SQLStore struct {
+	db      *sql.DB
+	dialect sqlDialect
+}
+
+// sqlDialect holds the SQL that differs between databases.
+type sqlDialect struct {
+	// jsonText returns an expression extracting, as text, the top level
+	// field named by the key placeholder from a JSON column.
+	jsonText func(column, key string) string
+	// jsonEquals returns a condition matching JSON columns whose top level
+	// field key holds the string value.
+	jsonEquals func(column, key, value string) string
+	// jsonHasKey returns a condition matching JSON columns with a top level field key.
+	jsonHasKey func(column, key string) string
+	// forUpdate is appended to the queries reading the rows a transaction writes next,
+	// locking them until it ends.
+	forUpdate string
+}
+
+// sqlQuery accumulates the conditions and arguments of a query.
+type sqlQuery struct {
+	conditions []string
+	args       []interface{}
+}
+
+// arg adds an argument and returns its placeholder.
+func (q *sqlQuery) arg(v interface{}) string {
+	q.args = append(q.args, v)
+	return fmt.Sprintf("$%d", len(q.args))
+}
+
+func (q *sqlQuery) where(condition string) {
+	q.conditions = append(q.conditions, condition)
+}
+
+func (q *sqlQuery) whereClause() string {
+	if len(q.conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(q.conditions, " AND ")
+}
+
+// newSQLStore opens the database and applies the pending migrations found in migrations.
+func newSQLStore(driverName, dsn string, migrations fs.FS, dialect sqlDialect) (*SQLStore, error) {
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	if err := migrate(db, migrations); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrating database: %w", err)
+	}
+
+	return &SQLStore{db: db, dialect: dialect}, nil
+}
+
+// migrate applies, in lexical order, every *.sql file from migrations
+// that is not yet recorded in the schema_migrations table.
+func migrate(db *sql.DB, migrations fs.FS) error {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (version TEXT PRIMARY KEY)`)
+	if err != nil {
+		return err
+	}
+
+	names, err := fs.Glob(migrations, "*.sql")
+	if err != nil {
+		return err
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		version := strings.TrimSuffix(name, ".sql")
+
+		var applied int
+		err := db.QueryRow(`SELECT COUNT(*) FROM schema_migrations WHERE version = $1`, version).Scan(&applied)
+		if err != nil {
+			return err
+		}
+		if applied > 0 {
+			continue
+		}
+
+		script, err := fs.ReadFile(migrations, name)
+		if err != nil {
+			return err
+		}
+
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(string(script)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if _, err := tx.Exec(`INSERT INTO schema_migrations (version) VALUES ($1)`, version); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *SQLStore) CreateConsumer(c *v1.Consumer) error {
+	labels, err := json.Marshal(labelsToMap(c.Labels))
+	if err != nil {
+		return err
+	}
+
+	result, err := s.db.Exec(
+		`INSERT INTO consumers (id, labels, deletion_timestamp) VALUES ($1, $2, $3) ON CONFLICT (id) DO NOTHING`,
+		c.Id, string(labels), c.DeletionTimestamp)
+	if err != nil {
+		return err
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return &ErrorAlreadyExists{Kind: "Consumer"}
+	}
+	return nil
+}
+
+func (s *SQLStore) SetConsumerDeletionTimestamp(consumerID string, expected, deletionTimestamp int64) (*v1.Consumer, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	old, err := scanConsumer(consumerID, tx.QueryRow(
+		`SELECT labels, deletion_timestamp FROM consumers WHERE id = $1`+s.dialect.forUpdate, consumerID))
+	if err != nil {
+		return nil, err
+	}
+	if old.DeletionTimestamp != expected {
+		return nil, &ErrorConflict{}
+	}
+	if _, err := tx.Exec(`UPDATE consumers SET deletion_timestamp = $1 WHERE id = $2`, deletionTimestamp, consumerID); err != nil {
+		return nil, err
+	}
+	return old, tx.Commit()
+}
+
+func (s *SQLStore) UpdateConsumer(c *v1.Consumer) (*v1.Consumer, error) {
+	labels, err := json.Marshal(labelsToMap(c.Labels))
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	old, err := scanConsumer(c.Id, tx.QueryRow(
+		`SELECT labels, deletion_timestamp FROM consumers WHERE id = $1`+s.dialect.forUpdate, c.Id))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(`UPDATE consumers SET labels = $1 WHERE id = $2`, string(labels), c.Id); err != nil {
+		return nil, err
+	}
+	return old, tx.Commit()
+}
+
+func (s *SQLStore) GetConsumer(consumerID string) (*v1.Consumer, error) {
+	return scanConsumer(consumerID, s.db.QueryRow(`SELECT labels, deletion_timestamp FROM consumers WHERE id = $1`, consumerID))
+}
+
+// scanConsumer returns the consumer of the row holding its labels and deletion timestamp.
+func scanConsumer(consumerID string, row *sql.Row) (*v1.Consumer, error) {
+	var (
+		labels            []byte
+		deletionTimestamp int64
+	)
+	err := row.Scan(&labels, &deletionTimestamp)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, &ErrorNotFound{Kind: "Consumer"}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return newConsumer(consumerID, labels, deletionTimestamp)
+}
+
+func newConsumer(consumerID string, labels []byte, deletionTimestamp int64) (*v1.Consumer, error) {
+	labelMap := map[string]string{}
+	if err := json.Unmarshal(labels, &labelMap); err != nil {
+		return nil, err
+	}
+
+	return &v1.Consumer{
+		Id:                consumerID,
+		Labels:            labelsFromMap(labelMap),
+		DeletionTimestamp: deletionTimestamp,
+	}, nil
+}
+
+// ListConsumers evaluates the label selector in the database, except for the
+// numeric comparisons which are applied on the returned rows.
+func (s *SQLStore) ListConsumers(opts ConsumerListOptions) ([]*v1.Consumer, error) {
+	var consumers []*v1.Consumer
+
+	after := opts.After
+	for {
+		q := &sqlQuery{}
+		q.where("id > " + q.arg(after))
+		s.whereLabels(q, opts.Selector)
+
+		query := `SELECT id, labels, deletion_timestamp FROM consumers` + q.whereClause() + ` ORDER BY id`
+		if opts.Limit > 0 {
+			query += ` LIMIT ` + q.arg(opts.Limit)
+		}
+
+		rows, err := s.db.Query(query, q.args...)
+		if err != nil {
+			return nil, err
+		}
+
+		n := 0
+		for rows.Next() && (opts.Limit == 0 || len(consumers) < opts.Limit) {
+			n++
+
+			var (
+				labels            []byte
+				deletionTimestamp int64
+			)
+			if err := rows.Scan(&after, &labels, &deletionTimestamp); err != nil {
+				rows.Close()
+				return nil, err
+			}
+
+			c, err := newConsumer(after, labels, deletionTimestamp)
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+			if consumerMatches(opts.Selector, c) {
+				consumers = append(consumers, c)
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+
+		if opts.Limit == 0 || n < opts.Limit || len(consumers) == opts.Limit {
+			return consumers, nil
+		}
+	}
+}
+
+// whereLabels translates the label selector requirements to conditions on the labels column.
+func (s *SQLStore) whereLabels(q *sqlQuery, selector labels.Selector) {
+	if selector == nil {
+		return
+	}
+
+	requirements, _ := selector.Requirements()
+	for _, r := range requirements {
+		key := q.arg(r.Key())
+		value := s.dialect.jsonText("labels", key)
+
+		var values []string
+		for _, v := range r.Values().List() {
+			values = append(values, q.arg(v))
+		}
+
+		switch r.Operator() {
+		case selection.Equals, selection.DoubleEquals:
+			q.where(s.dialect.jsonEquals("labels", key, values[0]))
+		case selection.NotEquals:
+			q.where("(" + value + " IS NULL OR " + value + " <> " + values[0] + ")")
+		case selection.In:
+			q.where(value + " IN (" + strings.Join(values, ", ") + ")")
+		case selection.NotIn:
+			q.where("(" + value + " IS NULL OR " + value + " NOT IN (" + strings.Join(values, ", ") + "))")
+		case selection.Exists:
+			q.where(s.dialect.jsonHasKey("labels", key))
+		case selection.DoesNotExist:
+			q.where(value + " IS NULL")
+		}
+	}
+}
+
+func (s *SQLStore) DeleteConsumer(consumerID string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM consumer_crds WHERE consumer_id = $1`, consumerID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM consumers WHERE id = $1`, consumerID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *SQLStore) PutCRD(c *CRD) error {
+	object, err := json.Marshal(c.Object.Object)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(
+		`INSERT INTO consumer_crds (consumer_id, name, object) VALUES ($1, $2, $3)
+		ON CONFLICT (consumer_id, name) DO UPDATE SET object = excluded.object`,
+		c.ConsumerId, c.Name, string(object))
+	return err
+}
+
+const crdColumns = `consumer_id, name, object`
+
+// scanCRD reads a row holding crdColumns.
+func scanCRD(row rowScanner) (*CRD, error) {
+	c := CRD{}
+
+	var object []byte
+	if err := row.Scan(&c.ConsumerId, &c.Name, &object); err != nil {
+		return nil, err
+	}
+
+	if err := utiljson.Unmarshal(object, &c.Object.Object); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+func (s *SQLStore) GetCRD(consumerID, name string) (*CRD, error) {
+	c, err := scanCRD(s.db.QueryRow(
+		`SELECT `+crdColumns+` FROM consumer_crds WHERE consumer_id = $1 AND name = $2`, consumerID, name))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, &ErrorNotFound{Kind: "CustomResourceDefinition"}
+	}
+	return c, err
+}
+
+func (s *SQLStore) ListCRDs(consumerID string) ([]*CRD, error) {
+	rows, err := s.db.Query(
+		`SELECT `+crdColumns+` FROM consumer_crds WHERE consumer_id = $1 ORDER BY name`, consumerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var crds []*CRD
+	for rows.Next() {
+		c, err := scanCRD(rows)
+		if err != nil {
+			return nil, err
+		}
+		crds = append(crds, c)
+	}
+	return crds, rows.Err()
+}
+
+func (s *SQLStore) DeleteCRD(consumerID, name string) error {
+	_, err := s.db.Exec(`DELETE FROM consumer_crds WHERE consumer_id = $1 AND name = $2`, consumerID, name)
+	return err
+}
+
+func (s *SQLStore) CreateResource(r *Resource) error {
+	object, err := json.Marshal(r.Object.Object)
+	if err != nil {
+		return err
+	}
+
+	status, err := json.Marshal(&r.Status)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.db.Exec(
+		`INSERT INTO resources (id, consumer_id, generation, object, status, observed_generation, deletion_timestamp,
+			api_version, kind, namespace, name)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (id) DO NOTHING`,
+		r.Id, r.ConsumerId, r.ResourceGenerationID, string(object), string(status), r.ObservedGenerationID, r.DeletionTimestamp,
+		r.Object.GetAPIVersion(), r.Object.GetKind(), r.Object.GetNamespace(), r.Object.GetName())
+	if err != nil {
+		return err
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return &ErrorAlreadyExists{Kind: "Resource"}
+	}
+	return nil
+}
+
+func (s *SQLStore) UpdateResource(r *Resource, expectedGenerationID int64) error {
+	object, err := json.Marshal(r.Object.Object)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.db.Exec(
+		`UPDATE resources SET
+			consumer_id = $1,
+			generation = $2,
+			object = $3,
+			deletion_timestamp = $4,
+			api_version = $5,
+			kind = $6,
+			namespace = $7,
+			name = $8
+		WHERE id = $9 AND generation = $10`,
+		r.ConsumerId, r.ResourceGenerationID, string(object), r.DeletionTimestamp,
+		r.Object.GetAPIVersion(), r.Object.GetKind(), r.Object.GetNamespace(), r.Object.GetName(),
+		r.Id, expectedGenerationID)
+	if err != nil {
+		return err
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return nil
+	}
+
+	// tell a missing resource from a concurrent update
+	if _, err := s.GetResource(r.Id); err != nil {
+		return err
+	}
+	return &ErrorConflict{}
+}
+
+const resourceColumns = `id, consumer_id, generation, object, status, observed_generation, deletion_timestamp`
+
+// rowScanner is implemented by *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanResource reads a row holding resourceColumns.
+func scanResource(row rowScanner) (*Resource, error) {
+	r := Resource{}
+
+	var object, status []byte
+	err := row.Scan(&r.Id, &r.ConsumerId, &r.ResourceGenerationID, &object, &status, &r.ObservedGenerationID, &r.DeletionTimestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	// utiljson keeps integers as int64, like the other stores do.
+	if err := utiljson.Unmarshal(object, &r.Object.Object); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(status, &r.Status); err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+func (s *SQLStore) GetResource(resourceID string) (*Resource, error) {
+	r, err := scanResource(s.db.QueryRow(`SELECT `+resourceColumns+` FROM resources WHERE id = $1`, resourceID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, &ErrorNotFound{Kind: "Resource"}
+	}
+	return r, err
+}
+
+func (s *SQLStore) DeleteResource(resourceID string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM resource_revisions WHERE resource_id = $1`, resourceID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM resources WHERE id = $1`, resourceID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ListResources filters in the database, except for the condition which is matched on the returned rows.
+func (s *SQLStore) ListResources(opts ResourceListOptions) ([]*Resource, error) {
+	var resources []*Resource
+
+	after := opts.After
+	for {
+		q := &sqlQuery{}
+		q.where("id > " + q.arg(after))
+		for _, filter := range []struct{ column, value string }{
+			{"consumer_id", opts.ConsumerId},
+			{"api_version", opts.APIVersion},
+			{"kind", opts.Kind},
+			{"namespace", opts.Namespace},
+			{"name", opts.Name},
+		} {
+			if filter.value != "" {
+				q.where(filter.column + " = " + q.arg(filter.value))
+			}
+		}
+
+		query := `SELECT ` + resourceColumns + ` FROM resources` + q.whereClause() + ` ORDER BY id`
+		if opts.Limit > 0 {
+			query += ` LIMIT ` + q.arg(opts.Limit)
+		}
+
+		rows, err := s.db.Query(query, q.args...)
+		if err != nil {
+			return nil, err
+		}
+
+		n := 0
+		for rows.Next() && (opts.Limit == 0 || len(resources) < opts.Limit) {
+			n++
+
+			r, err := scanResource(rows)
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+			after = r.Id
+
+			if resourceMatches(opts, r) {
+				resources = append(resources, r)
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+
+		if opts.Limit == 0 || n < opts.Limit || len(resources) == opts.Limit {
+			return resources, nil
+		}
+	}
+}
+
+func (s *SQLStore) SetStatusResource(consumerID, resourceID string, status *StatusMessage) error {
+	statusJson, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.db.Exec(
+		`UPDATE resources SET status = $1, observed_generation = $2
+		WHERE id = $3 AND consumer_id = $4 AND observed_generation <= $2 AND generation >= $2`,
+		string(statusJson), status.ResourceGenerationID, resourceID, consumerID)
+	if err != nil {
+		return err
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return nil
+	}
+
+	// tell why the status was not stored
+	r, err := s.GetResource(resourceID)
+	if err != nil {
+		return err
+	}
+	if err := checkStatus(r, consumerID, status); err != nil {
+		return err
+	}
+	return &ErrorConflict{}
+}
+
+func (s *SQLStore) PutRevision(r *Revision) error {
+	object, err := json.Marshal(r.Object.Object)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(
+		`INSERT INTO resource_revisions (resource_id, generation, object, author, creation_timestamp)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (resource_id, generation) DO UPDATE SET
+			object = excluded.object,
+			author = excluded.author,
+			creation_timestamp = excluded.creation_timestamp`,
+		r.ResourceId, r.ResourceGenerationID, string(object), r.Author, r.CreationTimestamp)
+	return err
+}
+
+const revisionColumns = `resource_id, generation, object, author, creation_timestamp`
+
+// scanRevision reads a row holding revisionColumns.
+func scanRevision(row rowScanner) (*Revision, error) {
+	r := Revision{}
+
+	var object []byte
+	err := row.Scan(&r.ResourceId, &r.ResourceGenerationID, &object, &r.Author, &r.CreationTimestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := utiljson.Unmarshal(object, &r.Object.Object); err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+func (s *SQLStore) GetRevision(resourceID string, generationID int64) (*Revision, error) {
+	r, err := scanRevision(s.db.QueryRow(
+		`SELECT `+revisionColumns+` FROM resource_revisions WHERE resource_id = $1 AND generation = $2`,
+		resourceID, generationID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, &ErrorNotFound{Kind: "Revision"}
+	}
+	return r, err
+}
+
+func (s *SQLStore) ListRevisions(resourceID string) ([]*Revision, error) {
+	rows, err := s.db.Query(
+		`SELECT `+revisionColumns+` FROM resource_revisions WHERE resource_id = $1 ORDER BY generation DESC`,
+		resourceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []*Revision
+	for rows.Next() {
+		r, err := scanRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, r)
+	}
+	return revisions, rows.Err()
+}
+
+func (s *SQLStore) DeleteRevisions(resourceID string, beforeGenerationID int64) error {
+	_, err := s.db.Exec(`DELETE FROM resource_revisions WHERE resource_id = $1 AND generation < $2`,
+		resourceID, beforeGenerationID)
+	return err
+}
+
+func (s *SQLStore) CreateIdempotencyRecord(r *IdempotencyRecord, now int64) error {
+	// only an expired record is replaced, no row is affected otherwise
+	result, err := s.db.Exec(
+		`INSERT INTO idempotency_records (idempotency_key, request_hash, response, expiration_timestamp)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (idempotency_key) DO UPDATE SET
+			request_hash = excluded.request_hash,
+			response = excluded.response,
+			expiration_timestamp = excluded.expiration_timestamp
+		WHERE idempotency_records.expiration_timestamp <= $5`,
+		r.IdempotencyKey, r.RequestHash, r.Response, r.ExpirationTimestamp, now)
+	if err != nil {
+		return err
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return &ErrorAlreadyExists{Kind: "IdempotencyRecord"}
+	}
+	return nil
+}
+
+func (s *SQLStore) UpdateIdempotencyRecord(r *IdempotencyRecord, leaseExpiration int64) error {
+	result, err := s.db.Exec(
+		`UPDATE idempotency_records SET response = $1, expiration_timestamp = $2
+		WHERE idempotency_key = $3 AND request_hash = $4 AND expiration_timestamp = $5`,
+		r.Response, r.ExpirationTimestamp, r.IdempotencyKey, r.RequestHash, leaseExpiration)
+	if err != nil {
+		return err
+	}
+	return s.idempotencyClaimResult(r.IdempotencyKey, result)
+}
+
+func (s *SQLStore) GetIdempotencyRecord(key string) (*IdempotencyRecord, error) {
+	r := IdempotencyRecord{}
+	err := s.db.QueryRow(
+		`SELECT idempotency_key, request_hash, response, expiration_timestamp FROM idempotency_records
+		WHERE idempotency_key = $1`, key).
+		Scan(&r.IdempotencyKey, &r.RequestHash, &r.Response, &r.ExpirationTimestamp)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, &ErrorNotFound{Kind: "IdempotencyRecord"}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (s *SQLStore) DeleteIdempotencyRecord(r *IdempotencyRecord) error {
+	result, err := s.db.Exec(
+		`DELETE FROM idempotency_records
+		WHERE idempotency_key = $1 AND request_hash = $2 AND expiration_timestamp = $3`,
+		r.IdempotencyKey, r.RequestHash, r.ExpirationTimestamp)
+	if err != nil {
+		return err
+	}
+	return s.idempotencyClaimResult(r.IdempotencyKey, result)
+}
+
+// idempotencyClaimResult returns the error of a write of the record of key conditioned on its claim.
+func (s *SQLStore) idempotencyClaimResult(key string, result sql.Result) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return nil
+	}
+
+	// tell a missing record from one taken over
+	if _, err := s.GetIdempotencyRecord(key); err != nil {
+		return err
+	}
+	return &ErrorConflict{}
+}
+
+func (s *SQLStore) DeleteExpiredIdempotencyRecords(now int64) error {
+	_, err := s.db.Exec(`DELETE FROM idempotency_records WHERE expiration_timestamp <= $1`, now)
+	return err
+}
+
+func (s *SQLStore) PutDeadLetter(d *DeadLetter) error {
+	_, err := s.db.Exec(
+		`INSERT INTO dead_letters (id, topic, consumer_id, resource_id, payload, reason, error,
+			received_timestamp, expiration_timestamp)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		d.Id, d.Topic, d.ConsumerId, d.ResourceId, d.Payload, d.Reason, d.Error,
+		d.ReceivedTimestamp, d.ExpirationTimestamp)
+	return err
+}
+
+func (s *SQLStore) GetDeadLetter(id string) (*DeadLetter, error) {
+	d := DeadLetter{}
+	err := s.db.QueryRow(
+		`SELECT id, topic, consumer_id, resource_id, payload, reason, error, received_timestamp, expiration_timestamp
+		FROM dead_letters WHERE id = $1`, id).
+		Scan(&d.Id, &d.Topic, &d.ConsumerId, &d.ResourceId, &d.Payload, &d.Reason, &d.Error,
+			&d.ReceivedTimestamp, &d.ExpirationTimestamp)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, &ErrorNotFound{Kind: "DeadLetter"}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (s *SQLStore) ListDeadLetters(opts DeadLetterListOptions) ([]*DeadLetter, error) {
+	q := &sqlQuery{}
+	q.where("id > " + q.arg(opts.After))
+	if opts.ConsumerId != "" {
+		q.where("consumer_id = " + q.arg(opts.ConsumerId))
+	}
+	if opts.Reason != "" {
+		q.where("reason = " + q.arg(opts.Reason))
+	}
+
+	query := `SELECT id, topic, consumer_id, resource_id, payload, reason, error, received_timestamp, expiration_timestamp
+		FROM dead_letters` + q.whereClause() + ` ORDER BY id`
+	if opts.Limit > 0 {
+		query += ` LIMIT ` + q.arg(opts.Limit)
+	}
+
+	rows, err := s.db.Query(query, q.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deadLetters []*DeadLetter
+	for rows.Next() {
+		d := DeadLetter{}
+		if err := rows.Scan(&d.Id, &d.Topic, &d.ConsumerId, &d.ResourceId, &d.Payload, &d.Reason, &d.Error,
+			&d.ReceivedTimestamp, &d.ExpirationTimestamp); err != nil {
+			return nil, err
+		}
+		deadLetters = append(deadLetters, &d)
+	}
+	return deadLetters, rows.Err()
+}
+
+func (s *SQLStore) DeleteDeadLetter(id string) error {
+	_, err := s.db.Exec(`DELETE FROM dead_letters WHERE id = $1`, id)
+	return err
+}
+
+func (s *SQLStore) DeleteExpiredDeadLetters(now int64) error {
+	_, err := s.db.Exec(`DELETE FROM dead_letters WHERE expiration_timestamp <= $1`, now)
+	return err
+}
+
+// Close releases the database connections.
+func (s *SQLStore) Close() error {
+	return s.db.Close()
+}
+
+func labelsToMap(labels []*v1.ConsumerLabel) map[string]string {
+	m := make(map[string]string, len(labels))
+	for _, l := range labels {
+		m[l.Key] = l.Value
+	}
+	return m
+}
+
+// labelsFromMap converts labels back to their API form, sorted by key.
+func labelsFromMap(m map[string]string) []*v1.ConsumerLabel {
+	labels := make([]*v1.ConsumerLabel, 0, len(m))
+	for k, v := range m {
+		labels = append(labels, &v1.ConsumerLabel{Key: k, Value: v})
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i].Key < labels[j].Key
+	})
+	return labels
+}
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"embed"
+	"io/fs"
+	"net/url"
+	"os"
+
+	_ "modernc.org/sqlite"
+)
+
+const sqlitePath = "SQLITE_PATH"
+
+const defaultSQLitePath = "maestro.db"
+
+//go:embed migrations/sqlite/*.sql
+var sqliteMigrations embed.FS
+
+// NewSQLiteStore opens the SQLite database file named by SQLITE_PATH
+// (maestro.db by default), switches it to WAL mode and brings its schema up to date.
+func NewSQLiteStore() (*SQLStore, error) {
+	path := os.Getenv(sqlitePath)
+	if len(path) == 0 {
+		path = defaultSQLitePath
+	}
+
+	params := url.Values{}
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Add("_pragma", "foreign_keys(1)")
+	// transactions take the write lock when they begin, SQLite has no SELECT FOR UPDATE
+	params.Add("_txlock", "immediate")
+	dsn := "file:" + path + "?" + params.Encode()
+
+	migrations, err := fs.Sub(sqliteMigrations, "migrations/sqlite")
+	if err != nil {
+		return nil, err
+	}
+
+	jsonText := func(column, key string) string {
+		// quote the key, label keys usually contain dots
+		return column + " ->> ('$.' || json_quote(" + key + "))"
+	}
+
+	return newSQLStore("sqlite", dsn, migrations, sqlDialect{
+		jsonText: jsonText,
+		jsonEquals: func(column, key, value string) string {
+			return jsonText(column, key) + " = " + value
+		},
+		jsonHasKey: func(column, key string) string {
+			return jsonText(column, key) + " IS NOT NULL"
+		},
+	})
+}
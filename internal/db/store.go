@@ -0,0 +1,201 @@
+package db
+
+import (
+	"fmt"
+
+	v1 "github.com/kube-orchestra/maestro/proto/api/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	StoreDynamoDB = "dynamodb"
+	StoreMemory   = "memory"
+	StorePostgres = "postgres"
+	StoreSQLite   = "sqlite"
+)
+
+// Store persists consumers, resources, the status reported for them and the status messages rejected.
+type Store interface {
+	// CreateConsumer stores a new consumer, and returns an *ErrorAlreadyExists
+	// when there is already a consumer with the same ID.
+	CreateConsumer(c *v1.Consumer) error
+	// SetConsumerDeletionTimestamp replaces the deletion timestamp of the consumer while it is still
+	// expected, and returns the consumer as it was before. It returns an *ErrorNotFound when there is
+	// no consumer and an *ErrorConflict when its deletion timestamp is no longer expected.
+	SetConsumerDeletionTimestamp(consumerID string, expected, deletionTimestamp int64) (*v1.Consumer, error)
+	// UpdateConsumer replaces the labels of the stored consumer with those of c, and returns the
+	// consumer as it was before or an *ErrorNotFound when there is none.
+	// The deletion timestamp is left as stored.
+	UpdateConsumer(c *v1.Consumer) (*v1.Consumer, error)
+	GetConsumer(consumerID string) (*v1.Consumer, error)
+	ListConsumers(opts ConsumerListOptions) ([]*v1.Consumer, error)
+	// DeleteConsumer deletes the consumer and its CRDs.
+	DeleteConsumer(consumerID string) error
+
+	// PutCRD creates or replaces the CRD of the consumer with the same name.
+	PutCRD(c *CRD) error
+	GetCRD(consumerID, name string) (*CRD, error)
+	// ListCRDs returns the CRDs of a consumer ordered by name.
+	ListCRDs(consumerID string) ([]*CRD, error)
+	DeleteCRD(consumerID, name string) error
+
+	// CreateResource stores a new resource, and returns an *ErrorAlreadyExists
+	// when there is already a resource with the same ID.
+	CreateResource(r *Resource) error
+	// UpdateResource replaces the stored resource only if it is still at expectedGenerationID,
+	// and returns an *ErrorNotFound when there is none and an *ErrorConflict otherwise.
+	// The status and observed generation are left as stored, only SetStatusResource writes them.
+	UpdateResource(r *Resource, expectedGenerationID int64) error
+	GetResource(resourceID string) (*Resource, error)
+	// DeleteResource deletes the resource and its revisions.
+	DeleteResource(resourceID string) error
+	ListResources(opts ResourceListOptions) ([]*Resource, error)
+	// SetStatusResource stores the status reported by the consumer for its resource, and returns
+	// an *ErrorConsumerMismatch when the resource belongs to another consumer, an *ErrorStaleStatus
+	// when the status is for an older generation than the stored one and an *ErrorUnknownGeneration
+	// when it is for a generation the resource has not reached.
+	SetStatusResource(consumerID, resourceID string, status *StatusMessage) error
+
+	PutRevision(r *Revision) error
+	GetRevision(resourceID string, generationID int64) (*Revision, error)
+	// ListRevisions returns the revisions of a resource, the latest first.
+	ListRevisions(resourceID string) ([]*Revision, error)
+	// DeleteRevisions deletes the revisions of a resource older than beforeGenerationID.
+	DeleteRevisions(resourceID string, beforeGenerationID int64) error
+
+	// CreateIdempotencyRecord stores a new record, replacing a record with the same key expired
+	// at the unix timestamp now in the same atomic write, and returns an *ErrorAlreadyExists
+	// when there is already a record with the same key that has not expired.
+	CreateIdempotencyRecord(r *IdempotencyRecord, now int64) error
+	// UpdateIdempotencyRecord replaces the record of r.IdempotencyKey with r while it still is the claim
+	// of the request of r.RequestHash expiring at leaseExpiration. It returns an *ErrorNotFound when the
+	// record is gone, and an *ErrorConflict when another request took it over.
+	UpdateIdempotencyRecord(r *IdempotencyRecord, leaseExpiration int64) error
+	GetIdempotencyRecord(key string) (*IdempotencyRecord, error)
+	// DeleteIdempotencyRecord deletes the record of r.IdempotencyKey while it still is the claim of the
+	// request of r.RequestHash expiring at r.ExpirationTimestamp. It returns an *ErrorNotFound when the
+	// record is gone, and an *ErrorConflict when another request took it over.
+	DeleteIdempotencyRecord(r *IdempotencyRecord) error
+	// DeleteExpiredIdempotencyRecords deletes the records expired at the unix timestamp now.
+	DeleteExpiredIdempotencyRecords(now int64) error
+
+	PutDeadLetter(d *DeadLetter) error
+	GetDeadLetter(id string) (*DeadLetter, error)
+	ListDeadLetters(opts DeadLetterListOptions) ([]*DeadLetter, error)
+	DeleteDeadLetter(id string) error
+	// DeleteExpiredDeadLetters deletes the dead letters expired at the unix timestamp now.
+	DeleteExpiredDeadLetters(now int64) error
+}
+
+// ConsumerListOptions selects the consumers returned by ListConsumers.
+// Consumers are listed in a stable order specific to each store.
+type ConsumerListOptions struct {
+	// Selector filters consumers by label, nil selects all of them.
+	Selector labels.Selector
+	// After is the ID of the last consumer of the previous page.
+	After string
+	// Limit is the maximum number of consumers returned, zero means no limit.
+	Limit int
+}
+
+func consumerMatches(selector labels.Selector, c *v1.Consumer) bool {
+	if selector == nil || selector.Empty() {
+		return true
+	}
+	return selector.Matches(labels.Set(labelsToMap(c.Labels)))
+}
+
+// ResourceListOptions selects the resources returned by ListResources.
+// Empty fields match every resource.
+// Resources are listed in a stable order specific to each store.
+type ResourceListOptions struct {
+	ConsumerId string
+	APIVersion string
+	Kind       string
+	Namespace  string
+	Name       string
+	// Condition matches resources whose status reports a condition
+	// with the same type and status.
+	Condition *metav1.Condition
+	// After is the ID of the last resource of the previous page.
+	After string
+	// Limit is the maximum number of resources returned, zero means no limit.
+	Limit int
+}
+
+// DeadLetterListOptions selects the dead letters returned by ListDeadLetters.
+// Empty fields match every dead letter.
+// Dead letters are listed by ID, except on DynamoDB which lists them in the stable order of its scan.
+type DeadLetterListOptions struct {
+	ConsumerId string
+	Reason     string
+	// After is the ID of the last dead letter of the previous page.
+	After string
+	// Limit is the maximum number of dead letters returned, zero means no limit.
+	Limit int
+}
+
+func deadLetterMatches(opts DeadLetterListOptions, d *DeadLetter) bool {
+	switch {
+	case opts.ConsumerId != "" && d.ConsumerId != opts.ConsumerId:
+		return false
+	case opts.Reason != "" && d.Reason != opts.Reason:
+		return false
+	}
+	return true
+}
+
+// checkStatus returns the error of setting the status reported by the consumer on r, nil when it can be set.
+func checkStatus(r *Resource, consumerID string, status *StatusMessage) error {
+	switch {
+	case r.ConsumerId != consumerID:
+		return &ErrorConsumerMismatch{ConsumerID: consumerID, ResourceConsumerID: r.ConsumerId}
+	case status.ResourceGenerationID < r.ObservedGenerationID:
+		return &ErrorStaleStatus{GenerationID: status.ResourceGenerationID, ObservedGenerationID: r.ObservedGenerationID}
+	case status.ResourceGenerationID > r.ResourceGenerationID:
+		return &ErrorUnknownGeneration{GenerationID: status.ResourceGenerationID, ResourceGenerationID: r.ResourceGenerationID}
+	}
+	return nil
+}
+
+func resourceMatches(opts ResourceListOptions, r *Resource) bool {
+	switch {
+	case opts.ConsumerId != "" && r.ConsumerId != opts.ConsumerId:
+		return false
+	case opts.APIVersion != "" && r.Object.GetAPIVersion() != opts.APIVersion:
+		return false
+	case opts.Kind != "" && r.Object.GetKind() != opts.Kind:
+		return false
+	case opts.Namespace != "" && r.Object.GetNamespace() != opts.Namespace:
+		return false
+	case opts.Name != "" && r.Object.GetName() != opts.Name:
+		return false
+	}
+
+	if opts.Condition != nil {
+		c := meta.FindStatusCondition(r.Status.ReconcileStatus.Conditions, opts.Condition.Type)
+		if c == nil || c.Status != opts.Condition.Status {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NewStore creates the Store backend identified by storeType.
+func NewStore(storeType string) (Store, error) {
+	switch storeType {
+	case StoreDynamoDB:
+		return NewDynamoDBStore()
+	case StoreMemory:
+		return NewMemoryStore(), nil
+	case StorePostgres:
+		return NewPostgresStore()
+	case StoreSQLite:
+		return NewSQLiteStore()
+	default:
+		return nil, fmt.Errorf("unknown store type %q", storeType)
+	}
+}
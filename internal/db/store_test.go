@@ -0,0 +1,778 @@
+package db
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	v1 "github.com/kube-orchestra/maestro/proto/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// testStores returns an empty store of each backend that runs without external services,
+// and of PostgreSQL when POSTGRES_DSN names a database the tests may empty.
+func testStores(t *testing.T) map[string]Store {
+	t.Helper()
+
+	t.Setenv(sqlitePath, filepath.Join(t.TempDir(), "maestro.db"))
+	sqlite, err := NewSQLiteStore()
+	if err != nil {
+		t.Fatalf("failed to open the SQLite store: %v", err)
+	}
+
+	stores := map[string]Store{
+		"memory": NewMemoryStore(),
+		"sqlite": sqlite,
+	}
+
+	if os.Getenv(postgresDSN) != "" {
+		postgres, err := NewPostgresStore()
+		if err != nil {
+			t.Fatalf("failed to open the PostgreSQL store: %v", err)
+		}
+		_, err = postgres.db.Exec(`TRUNCATE consumers, consumer_crds, resources, resource_revisions,
+			idempotency_records, dead_letters`)
+		if err != nil {
+			t.Fatalf("failed to empty the PostgreSQL store: %v", err)
+		}
+		stores["postgres"] = postgres
+	}
+
+	return stores
+}
+
+func newTestResource(id, consumerID string) *Resource {
+	r := &Resource{
+		Id:                   id,
+		ConsumerId:           consumerID,
+		ResourceGenerationID: 1,
+		Object: unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"name":      id,
+				"namespace": "default",
+			},
+		}},
+	}
+	return r
+}
+
+func newTestStatus(generationID int64) *StatusMessage {
+	return &StatusMessage{
+		MessageMeta:     MessageMeta{ResourceGenerationID: generationID},
+		ReconcileStatus: ReconcileStatus{ObservedGeneration: generationID},
+	}
+}
+
+func TestUpdateResourceKeepsStatus(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			if err := store.CreateResource(newTestResource("r1", "c1")); err != nil {
+				t.Fatal(err)
+			}
+
+			// the copy read before the update has no status yet
+			res, err := store.GetResource("r1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := store.SetStatusResource("c1", "r1", newTestStatus(1)); err != nil {
+				t.Fatal(err)
+			}
+
+			res.ResourceGenerationID = 2
+			res.Object.SetLabels(map[string]string{"app": "nginx"})
+			if err := store.UpdateResource(res, 1); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := store.GetResource("r1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.ResourceGenerationID != 2 || got.Object.GetLabels()["app"] != "nginx" {
+				t.Errorf("update not stored: generation %d, labels %v", got.ResourceGenerationID, got.Object.GetLabels())
+			}
+			if got.ObservedGenerationID != 1 || got.Status.ResourceGenerationID != 1 {
+				t.Errorf("status overwritten by the update: observed generation %d, status generation %d",
+					got.ObservedGenerationID, got.Status.ResourceGenerationID)
+			}
+
+			var conflict *ErrorConflict
+			if err := store.UpdateResource(res, 1); !errors.As(err, &conflict) {
+				t.Errorf("update of a stale generation returned %v, want *ErrorConflict", err)
+			}
+		})
+	}
+}
+
+func TestCreateIdempotencyRecord(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			var alreadyExists *ErrorAlreadyExists
+
+			first := &IdempotencyRecord{IdempotencyKey: "c1/key", RequestHash: "first", ExpirationTimestamp: 100}
+			if err := store.CreateIdempotencyRecord(first, 50); err != nil {
+				t.Fatal(err)
+			}
+			second := &IdempotencyRecord{IdempotencyKey: "c1/key", RequestHash: "second", ExpirationTimestamp: 200}
+			if err := store.CreateIdempotencyRecord(second, 99); !errors.As(err, &alreadyExists) {
+				t.Fatalf("creation over a record that has not expired returned %v, want *ErrorAlreadyExists", err)
+			}
+
+			// concurrent retries taking over the expired record, only one of them wins
+			var (
+				wg   sync.WaitGroup
+				mu   sync.Mutex
+				wins int
+			)
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					var alreadyExists *ErrorAlreadyExists
+					err := store.CreateIdempotencyRecord(second, 100)
+					if err != nil && !errors.As(err, &alreadyExists) {
+						t.Error(err)
+						return
+					}
+					if err == nil {
+						mu.Lock()
+						wins++
+						mu.Unlock()
+					}
+				}()
+			}
+			wg.Wait()
+			if wins != 1 {
+				t.Errorf("%d retries took over the expired record, want 1", wins)
+			}
+
+			stored, err := store.GetIdempotencyRecord("c1/key")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored.RequestHash != "second" || stored.ExpirationTimestamp != 200 {
+				t.Errorf("got record %+v, want the one taking over the expired record", stored)
+			}
+		})
+	}
+}
+
+func newTestConsumer(id string, labels map[string]string) *v1.Consumer {
+	return &v1.Consumer{Id: id, Labels: labelsFromMap(labels)}
+}
+
+func consumerIDs(consumers []*v1.Consumer) []string {
+	ids := []string{}
+	for _, c := range consumers {
+		ids = append(ids, c.Id)
+	}
+	return ids
+}
+
+func resourceIDs(resources []*Resource) []string {
+	ids := []string{}
+	for _, r := range resources {
+		ids = append(ids, r.Id)
+	}
+	return ids
+}
+
+func TestConsumers(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			var (
+				notFound      *ErrorNotFound
+				alreadyExists *ErrorAlreadyExists
+			)
+
+			if _, err := store.GetConsumer("c1"); !errors.As(err, &notFound) {
+				t.Fatalf("got %v reading a missing consumer, want *ErrorNotFound", err)
+			}
+
+			if err := store.CreateConsumer(newTestConsumer("c1", map[string]string{"env": "prod"})); err != nil {
+				t.Fatal(err)
+			}
+			if err := store.CreateConsumer(newTestConsumer("c1", nil)); !errors.As(err, &alreadyExists) {
+				t.Fatalf("got %v creating a consumer twice, want *ErrorAlreadyExists", err)
+			}
+
+			var conflict *ErrorConflict
+			if _, err := store.SetConsumerDeletionTimestamp("c2", 0, 42); !errors.As(err, &notFound) {
+				t.Errorf("got %v marking a missing consumer as deleting, want *ErrorNotFound", err)
+			}
+			old, err := store.SetConsumerDeletionTimestamp("c1", 0, 42)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if old.DeletionTimestamp != 0 || !reflect.DeepEqual(labelsToMap(old.Labels), map[string]string{"env": "prod"}) {
+				t.Errorf("got consumer %v, want the one before the deletion", old)
+			}
+			if _, err := store.SetConsumerDeletionTimestamp("c1", 0, 43); !errors.As(err, &conflict) {
+				t.Errorf("got %v marking a consumer being deleted as deleting, want *ErrorConflict", err)
+			}
+			got, err := store.GetConsumer("c1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(labelsToMap(got.Labels), map[string]string{"env": "prod"}) || got.DeletionTimestamp != 42 {
+				t.Errorf("got consumer %v, want the first deletion timestamp and the labels kept", got)
+			}
+
+			crd := &CRD{ConsumerId: "c1", Name: "crontabs.stable.example.com", Object: unstructured.Unstructured{
+				Object: map[string]interface{}{"kind": "CustomResourceDefinition"},
+			}}
+			if err := store.PutCRD(crd); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := store.DeleteConsumer("c1"); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := store.GetConsumer("c1"); !errors.As(err, &notFound) {
+				t.Errorf("got %v reading a deleted consumer, want *ErrorNotFound", err)
+			}
+			if _, err := store.GetCRD("c1", crd.Name); !errors.As(err, &notFound) {
+				t.Errorf("got %v reading a CRD of a deleted consumer, want *ErrorNotFound", err)
+			}
+		})
+	}
+}
+
+func TestUpdateConsumer(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			var notFound *ErrorNotFound
+			if _, err := store.UpdateConsumer(newTestConsumer("c1", nil)); !errors.As(err, &notFound) {
+				t.Fatalf("got %v updating a missing consumer, want *ErrorNotFound", err)
+			}
+			if _, err := store.GetConsumer("c1"); !errors.As(err, &notFound) {
+				t.Fatalf("got %v reading the consumer after a failed update, want *ErrorNotFound", err)
+			}
+
+			c := newTestConsumer("c1", map[string]string{"env": "prod"})
+			c.DeletionTimestamp = 42
+			if err := store.CreateConsumer(c); err != nil {
+				t.Fatal(err)
+			}
+
+			old, err := store.UpdateConsumer(newTestConsumer("c1", map[string]string{"env": "dev"}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(labelsToMap(old.Labels), map[string]string{"env": "prod"}) || old.DeletionTimestamp != 42 {
+				t.Errorf("got consumer %v, want the one before the update", old)
+			}
+
+			got, err := store.GetConsumer("c1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(labelsToMap(got.Labels), map[string]string{"env": "dev"}) || got.DeletionTimestamp != 42 {
+				t.Errorf("got consumer %v, want the new labels and the stored deletion timestamp", got)
+			}
+
+			// every concurrent update sees the labels of the one before it
+			var (
+				wg   sync.WaitGroup
+				mu   sync.Mutex
+				seen = map[string]int{}
+			)
+			for i := 0; i < 8; i++ {
+				wg.Add(1)
+				go func(value string) {
+					defer wg.Done()
+					old, err := store.UpdateConsumer(newTestConsumer("c1", map[string]string{"env": value}))
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					mu.Lock()
+					seen[labelsToMap(old.Labels)["env"]]++
+					mu.Unlock()
+				}(string(rune('a' + i)))
+			}
+			wg.Wait()
+			for value, n := range seen {
+				if n > 1 {
+					t.Errorf("%d updates replaced the labels env=%s", n, value)
+				}
+			}
+		})
+	}
+}
+
+func TestListConsumers(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			for id, l := range map[string]map[string]string{
+				"c1": {"env": "prod", "replicas": "1"},
+				"c2": {"env": "prod", "tier": "edge", "replicas": "3"},
+				"c3": {"env": "dev", "replicas": "5"},
+				"c4": {},
+			} {
+				if err := store.CreateConsumer(newTestConsumer(id, l)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			tests := []struct {
+				selector string
+				want     []string
+			}{
+				{"", []string{"c1", "c2", "c3", "c4"}},
+				{"env=prod", []string{"c1", "c2"}},
+				{"env!=prod", []string{"c3", "c4"}},
+				{"env in (dev, test)", []string{"c3"}},
+				{"env notin (dev)", []string{"c1", "c2", "c4"}},
+				{"tier", []string{"c2"}},
+				{"!tier", []string{"c1", "c3", "c4"}},
+				{"env=prod,!tier", []string{"c1"}},
+				{"replicas>2", []string{"c2", "c3"}},
+				{"replicas<2", []string{"c1"}},
+			}
+			for _, tt := range tests {
+				selector, err := labels.Parse(tt.selector)
+				if err != nil {
+					t.Fatal(err)
+				}
+				// without a limit, every selected consumer is listed
+				consumers, err := store.ListConsumers(ConsumerListOptions{Selector: selector})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := consumerIDs(consumers); !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("selector %q listed %v, want %v", tt.selector, got, tt.want)
+				}
+			}
+
+			// pages of the consumers in production
+			selector, _ := labels.Parse("env")
+			var pages [][]string
+			after := ""
+			for {
+				consumers, err := store.ListConsumers(ConsumerListOptions{Selector: selector, After: after, Limit: 2})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(consumers) == 0 {
+					break
+				}
+				pages = append(pages, consumerIDs(consumers))
+				after = consumers[len(consumers)-1].Id
+			}
+			if want := [][]string{{"c1", "c2"}, {"c3"}}; !reflect.DeepEqual(pages, want) {
+				t.Errorf("listed pages %v, want %v", pages, want)
+			}
+		})
+	}
+}
+
+func TestCRDs(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			for _, crdName := range []string{"crontabs.stable.example.com", "backups.example.com"} {
+				crd := &CRD{ConsumerId: "c1", Name: crdName, Object: unstructured.Unstructured{
+					Object: map[string]interface{}{"metadata": map[string]interface{}{"name": crdName}},
+				}}
+				if err := store.PutCRD(crd); err != nil {
+					t.Fatal(err)
+				}
+			}
+			other := &CRD{ConsumerId: "c2", Name: "others.example.com", Object: unstructured.Unstructured{
+				Object: map[string]interface{}{},
+			}}
+			if err := store.PutCRD(other); err != nil {
+				t.Fatal(err)
+			}
+
+			crds, err := store.ListCRDs("c1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, c := range crds {
+				names = append(names, c.Name)
+			}
+			if want := []string{"backups.example.com", "crontabs.stable.example.com"}; !reflect.DeepEqual(names, want) {
+				t.Errorf("listed CRDs %v, want %v", names, want)
+			}
+
+			got, err := store.GetCRD("c1", "backups.example.com")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Object.GetName() != "backups.example.com" {
+				t.Errorf("got CRD object %v", got.Object.Object)
+			}
+
+			if err := store.DeleteCRD("c1", "backups.example.com"); err != nil {
+				t.Fatal(err)
+			}
+			var notFound *ErrorNotFound
+			if _, err := store.GetCRD("c1", "backups.example.com"); !errors.As(err, &notFound) {
+				t.Errorf("got %v reading a deleted CRD, want *ErrorNotFound", err)
+			}
+		})
+	}
+}
+
+func TestResources(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			r := newTestResource("r1", "c1")
+			r.DeletionTimestamp = 7
+			if err := store.CreateResource(r); err != nil {
+				t.Fatal(err)
+			}
+			var alreadyExists *ErrorAlreadyExists
+			if err := store.CreateResource(newTestResource("r1", "c2")); !errors.As(err, &alreadyExists) {
+				t.Fatalf("got %v creating a resource twice, want *ErrorAlreadyExists", err)
+			}
+
+			got, err := store.GetResource("r1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.ConsumerId != "c1" || got.ResourceGenerationID != 1 || got.DeletionTimestamp != 7 ||
+				!reflect.DeepEqual(got.Object.Object, r.Object.Object) {
+				t.Errorf("got resource %+v, want %+v", got, r)
+			}
+
+			// the stored copy is not shared
+			got.Object.SetName("changed")
+			if again, _ := store.GetResource("r1"); again.Object.GetName() != "r1" {
+				t.Error("changing a read resource changed the stored one")
+			}
+
+			if err := store.PutRevision(&Revision{ResourceId: "r1", ResourceGenerationID: 1, Object: r.Object}); err != nil {
+				t.Fatal(err)
+			}
+			if err := store.DeleteResource("r1"); err != nil {
+				t.Fatal(err)
+			}
+			var notFound *ErrorNotFound
+			if _, err := store.GetResource("r1"); !errors.As(err, &notFound) {
+				t.Errorf("got %v reading a deleted resource, want *ErrorNotFound", err)
+			}
+			if revisions, err := store.ListRevisions("r1"); err != nil || len(revisions) != 0 {
+				t.Errorf("got revisions %v, error %v of a deleted resource, want none", revisions, err)
+			}
+			if err := store.UpdateResource(r, 1); !errors.As(err, &notFound) {
+				t.Errorf("got %v updating a deleted resource, want *ErrorNotFound", err)
+			}
+		})
+	}
+}
+
+func TestListResources(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			put := func(id, consumerID, apiVersion, kind, namespace string) {
+				r := newTestResource(id, consumerID)
+				r.Object.SetAPIVersion(apiVersion)
+				r.Object.SetKind(kind)
+				r.Object.SetNamespace(namespace)
+				if err := store.CreateResource(r); err != nil {
+					t.Fatal(err)
+				}
+			}
+			put("r1", "c1", "apps/v1", "Deployment", "default")
+			put("r2", "c1", "v1", "ConfigMap", "default")
+			put("r3", "c1", "apps/v1", "Deployment", "kube-system")
+			put("r4", "c2", "apps/v1", "Deployment", "default")
+
+			reconciled := newTestStatus(1)
+			reconciled.ReconcileStatus.Conditions = []metav1.Condition{
+				{Type: "Reconciled", Status: metav1.ConditionTrue, Reason: "Applied", LastTransitionTime: metav1.Unix(1, 0)},
+			}
+			if err := store.SetStatusResource("c1", "r1", reconciled); err != nil {
+				t.Fatal(err)
+			}
+			failed := newTestStatus(1)
+			failed.ReconcileStatus.Conditions = []metav1.Condition{
+				{Type: "Reconciled", Status: metav1.ConditionFalse, Reason: "Failed", LastTransitionTime: metav1.Unix(1, 0)},
+			}
+			if err := store.SetStatusResource("c1", "r3", failed); err != nil {
+				t.Fatal(err)
+			}
+
+			tests := []struct {
+				name string
+				opts ResourceListOptions
+				want []string
+			}{
+				{"all", ResourceListOptions{}, []string{"r1", "r2", "r3", "r4"}},
+				{"consumer", ResourceListOptions{ConsumerId: "c1"}, []string{"r1", "r2", "r3"}},
+				{"kind", ResourceListOptions{APIVersion: "apps/v1", Kind: "Deployment"}, []string{"r1", "r3", "r4"}},
+				{"namespace", ResourceListOptions{ConsumerId: "c1", Namespace: "default"}, []string{"r1", "r2"}},
+				{"name", ResourceListOptions{Name: "r2"}, []string{"r2"}},
+				{"condition", ResourceListOptions{
+					Condition: &metav1.Condition{Type: "Reconciled", Status: metav1.ConditionTrue},
+				}, []string{"r1"}},
+				{"other condition status", ResourceListOptions{
+					Condition: &metav1.Condition{Type: "Reconciled", Status: metav1.ConditionFalse},
+				}, []string{"r3"}},
+				{"page", ResourceListOptions{After: "r1", Limit: 2}, []string{"r2", "r3"}},
+				{"filtered page", ResourceListOptions{Kind: "Deployment", After: "r1", Limit: 1}, []string{"r3"}},
+			}
+			for _, tt := range tests {
+				resources, err := store.ListResources(tt.opts)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := resourceIDs(resources); !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("%s: listed %v, want %v", tt.name, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSetStatusResource(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			var notFound *ErrorNotFound
+			if err := store.SetStatusResource("c1", "r1", newTestStatus(1)); !errors.As(err, &notFound) {
+				t.Fatalf("got %v setting the status of a missing resource, want *ErrorNotFound", err)
+			}
+
+			r := newTestResource("r1", "c1")
+			r.ResourceGenerationID = 3
+			if err := store.CreateResource(r); err != nil {
+				t.Fatal(err)
+			}
+
+			var (
+				stale             *ErrorStaleStatus
+				unknownGeneration *ErrorUnknownGeneration
+				consumerMismatch  *ErrorConsumerMismatch
+			)
+			steps := []struct {
+				consumerID   string
+				generationID int64
+				check        func(error) bool
+				observed     int64
+			}{
+				{"c1", 2, func(err error) bool { return err == nil }, 2},
+				// the same generation reports the progress of the agent
+				{"c1", 2, func(err error) bool { return err == nil }, 2},
+				{"c1", 3, func(err error) bool { return err == nil }, 3},
+				{"c1", 2, func(err error) bool { return errors.As(err, &stale) }, 3},
+				{"c1", 4, func(err error) bool { return errors.As(err, &unknownGeneration) }, 3},
+				{"c2", 3, func(err error) bool { return errors.As(err, &consumerMismatch) }, 3},
+			}
+			for i, step := range steps {
+				err := store.SetStatusResource(step.consumerID, "r1", newTestStatus(step.generationID))
+				if !step.check(err) {
+					t.Fatalf("step %d: status of generation %d from %s returned %v", i, step.generationID, step.consumerID, err)
+				}
+
+				got, err := store.GetResource("r1")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got.ObservedGenerationID != step.observed || got.Status.ResourceGenerationID != step.observed {
+					t.Fatalf("step %d: observed generation %d, status of generation %d, want %d",
+						i, got.ObservedGenerationID, got.Status.ResourceGenerationID, step.observed)
+				}
+			}
+		})
+	}
+}
+
+func TestRevisions(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			r := newTestResource("r1", "c1")
+			for generationID := int64(1); generationID <= 4; generationID++ {
+				r.Object.SetLabels(map[string]string{"generation": string(rune('0' + generationID))})
+				revision := &Revision{
+					ResourceId:           "r1",
+					ResourceGenerationID: generationID,
+					Object:               *r.Object.DeepCopy(),
+					Author:               "test",
+					CreationTimestamp:    generationID * 10,
+				}
+				if err := store.PutRevision(revision); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			revision, err := store.GetRevision("r1", 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if revision.Object.GetLabels()["generation"] != "2" || revision.Author != "test" || revision.CreationTimestamp != 20 {
+				t.Errorf("got revision %+v of generation 2", revision)
+			}
+
+			if err := store.DeleteRevisions("r1", 3); err != nil {
+				t.Fatal(err)
+			}
+			revisions, err := store.ListRevisions("r1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			var generations []int64
+			for _, revision := range revisions {
+				generations = append(generations, revision.ResourceGenerationID)
+			}
+			if want := []int64{4, 3}; !reflect.DeepEqual(generations, want) {
+				t.Errorf("listed generations %v, want %v", generations, want)
+			}
+
+			var notFound *ErrorNotFound
+			if _, err := store.GetRevision("r1", 2); !errors.As(err, &notFound) {
+				t.Errorf("got %v reading a deleted revision, want *ErrorNotFound", err)
+			}
+		})
+	}
+}
+
+func TestIdempotencyRecords(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			var (
+				notFound *ErrorNotFound
+				conflict *ErrorConflict
+			)
+
+			record := &IdempotencyRecord{IdempotencyKey: "c1/key", RequestHash: "hash", ExpirationTimestamp: 100}
+			if err := store.CreateIdempotencyRecord(record, 0); err != nil {
+				t.Fatal(err)
+			}
+
+			completed := *record
+			completed.Response = []byte("response")
+			completed.ExpirationTimestamp = 200
+			if err := store.UpdateIdempotencyRecord(&completed, 99); !errors.As(err, &conflict) {
+				t.Errorf("got %v completing a record with another lease, want *ErrorConflict", err)
+			}
+			other := completed
+			other.RequestHash = "other"
+			if err := store.UpdateIdempotencyRecord(&other, 100); !errors.As(err, &conflict) {
+				t.Errorf("got %v completing a record of another request, want *ErrorConflict", err)
+			}
+			if err := store.UpdateIdempotencyRecord(&completed, 100); err != nil {
+				t.Fatal(err)
+			}
+			got, err := store.GetIdempotencyRecord("c1/key")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, &completed) {
+				t.Errorf("got record %+v, want %+v", got, &completed)
+			}
+
+			// the lease is over once the record is completed
+			if err := store.DeleteIdempotencyRecord(record); !errors.As(err, &conflict) {
+				t.Errorf("got %v releasing a completed record, want *ErrorConflict", err)
+			}
+			if err := store.DeleteIdempotencyRecord(&completed); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := store.GetIdempotencyRecord("c1/key"); !errors.As(err, &notFound) {
+				t.Errorf("got %v reading a deleted record, want *ErrorNotFound", err)
+			}
+			if err := store.DeleteIdempotencyRecord(&completed); !errors.As(err, &notFound) {
+				t.Errorf("got %v deleting a deleted record, want *ErrorNotFound", err)
+			}
+			if err := store.UpdateIdempotencyRecord(&completed, 200); !errors.As(err, &notFound) {
+				t.Errorf("got %v completing a deleted record, want *ErrorNotFound", err)
+			}
+
+			expired := &IdempotencyRecord{IdempotencyKey: "c1/expired", RequestHash: "hash", ExpirationTimestamp: 200}
+			kept := &IdempotencyRecord{IdempotencyKey: "c1/kept", RequestHash: "hash", ExpirationTimestamp: 300}
+			for _, r := range []*IdempotencyRecord{expired, kept} {
+				if err := store.CreateIdempotencyRecord(r, 0); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := store.DeleteExpiredIdempotencyRecords(200); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := store.GetIdempotencyRecord("c1/expired"); !errors.As(err, &notFound) {
+				t.Errorf("got %v reading an expired record, want *ErrorNotFound", err)
+			}
+			if _, err := store.GetIdempotencyRecord("c1/kept"); err != nil {
+				t.Errorf("got %v reading a record not expired yet", err)
+			}
+		})
+	}
+}
+
+func TestDeadLetters(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			for i, d := range []*DeadLetter{
+				{Id: "d1", ConsumerId: "c1", Reason: "INVALID_PAYLOAD", ExpirationTimestamp: 100},
+				{Id: "d2", ConsumerId: "c2", Reason: "INVALID_PAYLOAD", ExpirationTimestamp: 200},
+				{Id: "d3", ConsumerId: "c1", Reason: "UNKNOWN_RESOURCE", ExpirationTimestamp: 200},
+				{Id: "d4", Topic: "invalid", Reason: "INVALID_TOPIC", ExpirationTimestamp: 200},
+			} {
+				d.Payload = []byte{byte(i)}
+				d.Error = "rejected"
+				d.ReceivedTimestamp = int64(i)
+				if err := store.PutDeadLetter(d); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := store.GetDeadLetter("d2")
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := &DeadLetter{Id: "d2", ConsumerId: "c2", Payload: []byte{1}, Reason: "INVALID_PAYLOAD", Error: "rejected",
+				ReceivedTimestamp: 1, ExpirationTimestamp: 200}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got dead letter %+v, want %+v", got, want)
+			}
+
+			tests := []struct {
+				opts DeadLetterListOptions
+				want []string
+			}{
+				{DeadLetterListOptions{Limit: 10}, []string{"d1", "d2", "d3", "d4"}},
+				{DeadLetterListOptions{}, []string{"d1", "d2", "d3", "d4"}},
+				{DeadLetterListOptions{ConsumerId: "c1", Limit: 10}, []string{"d1", "d3"}},
+				{DeadLetterListOptions{Reason: "INVALID_PAYLOAD", Limit: 10}, []string{"d1", "d2"}},
+				{DeadLetterListOptions{After: "d1", Limit: 2}, []string{"d2", "d3"}},
+			}
+			for _, tt := range tests {
+				deadLetters, err := store.ListDeadLetters(tt.opts)
+				if err != nil {
+					t.Fatal(err)
+				}
+				ids := []string{}
+				for _, d := range deadLetters {
+					ids = append(ids, d.Id)
+				}
+				if !reflect.DeepEqual(ids, tt.want) {
+					t.Errorf("%+v listed %v, want %v", tt.opts, ids, tt.want)
+				}
+			}
+
+			if err := store.DeleteExpiredDeadLetters(100); err != nil {
+				t.Fatal(err)
+			}
+			if err := store.DeleteDeadLetter("d4"); err != nil {
+				t.Fatal(err)
+			}
+			deadLetters, err := store.ListDeadLetters(DeadLetterListOptions{Limit: 10})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(deadLetters) != 2 || deadLetters[0].Id != "d2" || deadLetters[1].Id != "d3" {
+				t.Errorf("got %d dead letters left, want d2 and d3", len(deadLetters))
+			}
+		})
+	}
+}
@@ -1,13 +1,18 @@
 package mqtt
 
 import (
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"os"
-	"strings"
+	"strconv"
+	"time"
 
 	mqtt "github.com/eclipse/paho.mqtt.golang"
 	"github.com/kube-orchestra/maestro/internal/db"
+	"k8s.io/apimachinery/pkg/api/meta"
 )
 
 const (
@@ -18,40 +23,65 @@ const (
 )
 
 type Connection struct {
-	Client          mqtt.Client
 	ResourceChannel chan db.ResourceMessage
+	store           db.Store
+	topics          *Topics
+	transport       transport
+	// deadLetterRetention is how long the rejected status messages are kept.
+	deadLetterRetention time.Duration
 }
 
-func NewConnection() *Connection {
-	client, err := NewClient()
+func NewConnection(store db.Store, deadLetterRetention time.Duration) *Connection {
+	topics, err := TopicsFromEnv()
 	if err != nil {
 		panic(err)
 	}
 
-	if token := client.Connect(); token.Wait() && token.Error() != nil {
-		panic(token.Error())
+	c := &Connection{
+		ResourceChannel: make(chan db.ResourceMessage),
+		store:           store,
+		topics:          topics,
+
+		deadLetterRetention: deadLetterRetention,
 	}
 
-	resourceChan := make(chan db.ResourceMessage)
-	return &Connection{
-		Client:          client,
-		ResourceChannel: resourceChan,
+	t, err := newTransport(c.messagePubHandler)
+	if err != nil {
+		panic(err)
 	}
+
+	c.transport = t
+	return c
 }
 
 func (c *Connection) StartSender() {
 	go func() {
 		for msg := range c.ResourceChannel {
-			topic := fmt.Sprintf("v1/%s/%s/content", msg.ConsumerId, msg.Id)
-			msgJson, _ := json.Marshal(msg)
-			token := c.Client.Publish(topic, 1, false, msgJson)
-			token.Wait()
+			msg.SentTimestamp = time.Now().Unix()
+			p := c.publication(&msg)
+			if err := c.transport.Publish(p); err != nil {
+				log.Printf("Failed to publish resource %s on %s: %v", msg.Id, p.topic, err)
+			}
 		}
 	}()
 }
 
+// publication returns the content message of msg, correlated with its generation.
+func (c *Connection) publication(msg *db.ResourceMessage) *publication {
+	msgJson, _ := json.Marshal(msg)
+	return &publication{
+		topic:           c.topics.Content.Topic(msg.ConsumerId, msg.Id),
+		payload:         msgJson,
+		responseTopic:   c.topics.Status.Topic(msg.ConsumerId, msg.Id),
+		correlationData: []byte(strconv.FormatInt(msg.ResourceGenerationID, 10)),
+		properties:      messageProperties(msg),
+	}
+}
+
 func (c *Connection) StartStatusReceiver() {
-	c.Client.Subscribe("v1/+/+/status", 1, messagePubHandler)
+	if err := c.transport.Subscribe(c.topics.Status.Filter()); err != nil {
+		panic(err)
+	}
 }
 
 var connectHandler mqtt.OnConnectHandler = func(client mqtt.Client) {
@@ -62,21 +92,174 @@ var connectLostHandler mqtt.ConnectionLostHandler = func(client mqtt.Client, err
 	fmt.Printf("Connect lost: %v", err)
 }
 
-var messagePubHandler mqtt.MessageHandler = func(client mqtt.Client, msg mqtt.Message) {
-	topicComponents := strings.Split(msg.Topic(), "/")
+// messagePubHandler ingests the status message msg. Messages that cannot be ingested
+// are logged, counted and stored as dead letters.
+func (c *Connection) messagePubHandler(msg *Message) {
+	statusMessages.Add("received", 1)
+
+	consumerID, resourceID, _ := c.topics.Status.Parse(msg.Topic)
+	defer func() {
+		if r := recover(); r != nil {
+			c.deadLetter(msg, consumerID, resourceID, reject(RejectInternalError, fmt.Errorf("panic: %v", r)))
+		}
+	}()
+
+	err := c.ingestStatus(msg)
+	var stale *db.ErrorStaleStatus
+	switch {
+	case errors.As(err, &stale):
+		// reports can arrive out of order, the status of a newer generation is already stored
+		log.Printf("Ignoring stale status on topic %s: %v", msg.Topic, err)
+		statusMessages.Add("stale", 1)
+	case err != nil:
+		c.deadLetter(msg, consumerID, resourceID, err)
+	default:
+		statusMessages.Add("ingested", 1)
+	}
+}
+
+func (c *Connection) ingestStatus(msg *Message) error {
+	consumerID, resourceID, ok := c.topics.Status.Parse(msg.Topic)
+	if !ok {
+		return reject(RejectInvalidTopic, fmt.Errorf("topic does not match %s", c.topics.Status.Filter()))
+	}
 
-	err := db.SetStatusResource(topicComponents[2], msg.Payload())
+	payload, err := withCorrelatedGeneration(msg.Payload, msg.CorrelationData)
 	if err != nil {
-		panic(err)
+		return err
+	}
+
+	status, err := decodeStatus(payload)
+	if err != nil {
+		return reject(RejectInvalidPayload, err)
+	}
+
+	deleted, err := c.finalizeDeletion(consumerID, resourceID, status)
+	if err != nil {
+		return storeRejection(err)
+	}
+	if deleted {
+		return nil
 	}
+
+	if err := c.store.SetStatusResource(consumerID, resourceID, status); err != nil {
+		return storeRejection(err)
+	}
+	return nil
 }
 
-func NewClient() (mqtt.Client, error) {
-	// mqtt.ERROR = log.New(os.Stdout, "E: ", 0)
-	// mqtt.CRITICAL = log.New(os.Stdout, "C: ", 0)
-	// mqtt.WARN = log.New(os.Stdout, "W: ", 0)
-	// mqtt.DEBUG = log.New(os.Stdout, "D: ", 0)
+// storeRejection returns the rejection of a status the store failed to ingest with err.
+func storeRejection(err error) error {
+	var (
+		notFound          *db.ErrorNotFound
+		consumerMismatch  *db.ErrorConsumerMismatch
+		unknownGeneration *db.ErrorUnknownGeneration
+		stale             *db.ErrorStaleStatus
+	)
+	switch {
+	case errors.As(err, &notFound):
+		return reject(RejectUnknownResource, err)
+	case errors.As(err, &consumerMismatch):
+		return reject(RejectConsumerMismatch, err)
+	case errors.As(err, &unknownGeneration):
+		return reject(RejectUnknownGeneration, err)
+	case errors.As(err, &stale):
+		return err
+	default:
+		return reject(RejectStoreError, err)
+	}
+}
+
+// withCorrelatedGeneration returns the status of statusData answering the generation of correlationData,
+// sent back by MQTT 5 agents: the generation is filled in when the status has none
+// and must match the one of the status otherwise.
+func withCorrelatedGeneration(statusData, correlationData []byte) ([]byte, error) {
+	if len(correlationData) == 0 {
+		return statusData, nil
+	}
+
+	generationID, err := strconv.ParseInt(string(correlationData), 10, 64)
+	if err != nil {
+		return nil, reject(RejectCorrelationMismatch, fmt.Errorf("invalid correlation data %q: %w", correlationData, err))
+	}
+
+	var status db.StatusMessage
+	if err := json.Unmarshal(statusData, &status); err != nil {
+		return nil, reject(RejectInvalidPayload, err)
+	}
 
+	switch status.ResourceGenerationID {
+	case generationID:
+		return statusData, nil
+	case 0:
+		status.ResourceGenerationID = generationID
+		return json.Marshal(&status)
+	default:
+		return nil, reject(RejectCorrelationMismatch, fmt.Errorf("status of generation %d has the correlation data of generation %d",
+			status.ResourceGenerationID, generationID))
+	}
+}
+
+// finalizeDeletion removes the resource from the store when the status reported by its consumer
+// reports the Deleted condition for the generation that requested its deletion.
+func (c *Connection) finalizeDeletion(consumerID, resourceID string, status *db.StatusMessage) (bool, error) {
+	if !meta.IsStatusConditionTrue(status.ReconcileStatus.Conditions, db.StatusMessageDeleted) {
+		return false, nil
+	}
+
+	res, err := c.store.GetResource(resourceID)
+	if err != nil {
+		return false, err
+	}
+
+	if res.ConsumerId != consumerID {
+		return false, &db.ErrorConsumerMismatch{ConsumerID: consumerID, ResourceConsumerID: res.ConsumerId}
+	}
+	if res.DeletionTimestamp == 0 || status.ResourceGenerationID != res.ResourceGenerationID {
+		return false, nil
+	}
+
+	if err := c.store.DeleteResource(resourceID); err != nil {
+		return false, err
+	}
+	if err := c.finalizeConsumerDeletion(consumerID); err != nil {
+		log.Printf("Failed to finalize the deletion of consumer %s: %v", consumerID, err)
+	}
+	return true, nil
+}
+
+// finalizeConsumerDeletion removes the consumer once the last of its resources is deleted,
+// when its deletion was requested with the CASCADE policy.
+func (c *Connection) finalizeConsumerDeletion(consumerID string) error {
+	consumer, err := c.store.GetConsumer(consumerID)
+	var notFound *db.ErrorNotFound
+	if errors.As(err, &notFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if consumer.DeletionTimestamp == 0 {
+		return nil
+	}
+
+	resources, err := c.store.ListResources(db.ResourceListOptions{ConsumerId: consumerID, Limit: 1})
+	if err != nil || len(resources) > 0 {
+		return err
+	}
+	return c.store.DeleteConsumer(consumerID)
+}
+
+// clientConfig is the configuration of the connection to the broker shared by the MQTT versions.
+type clientConfig struct {
+	clientID  string
+	brokerURL string
+	username  string
+	password  string
+	tlsConfig *tls.Config
+}
+
+func clientConfigFromEnv() (*clientConfig, error) {
 	clientID := os.Getenv(mqttClientID)
 	if len(clientID) == 0 {
 		return nil, fmt.Errorf("%s must be set", mqttClientID)
@@ -87,22 +270,53 @@ func NewClient() (mqtt.Client, error) {
 		return nil, fmt.Errorf("%s must be set", mqttBrokerURL)
 	}
 
+	tlsConfig, err := TLSConfigFromEnv(brokerURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// clients authenticated by their certificate need no password
+	clientCertificate := len(os.Getenv(mqttClientCertFile)) > 0
+
 	brokerUsername := os.Getenv(mqttBrokerUsername)
-	if len(brokerUsername) == 0 {
+	if len(brokerUsername) == 0 && !clientCertificate {
 		return nil, fmt.Errorf("%s must be set", mqttBrokerUsername)
 	}
 
 	brokerPassword := os.Getenv(mqttBrokerPassword)
-	if len(brokerPassword) == 0 {
+	if len(brokerPassword) == 0 && !clientCertificate {
 		return nil, fmt.Errorf("%s must be set", mqttBrokerPassword)
 	}
 
+	return &clientConfig{
+		clientID:  clientID,
+		brokerURL: brokerURL,
+		username:  brokerUsername,
+		password:  brokerPassword,
+		tlsConfig: tlsConfig,
+	}, nil
+}
+
+func NewClient(messageHandler mqtt.MessageHandler) (mqtt.Client, error) {
+	// mqtt.ERROR = log.New(os.Stdout, "E: ", 0)
+	// mqtt.CRITICAL = log.New(os.Stdout, "C: ", 0)
+	// mqtt.WARN = log.New(os.Stdout, "W: ", 0)
+	// mqtt.DEBUG = log.New(os.Stdout, "D: ", 0)
+
+	cfg, err := clientConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
 	opts := mqtt.NewClientOptions()
-	opts.AddBroker(brokerURL)
-	opts.SetClientID(clientID)
-	opts.SetUsername(brokerUsername)
-	opts.SetPassword(brokerPassword)
-	opts.SetDefaultPublishHandler(messagePubHandler)
+	opts.AddBroker(cfg.brokerURL)
+	opts.SetClientID(cfg.clientID)
+	opts.SetUsername(cfg.username)
+	opts.SetPassword(cfg.password)
+	if cfg.tlsConfig != nil {
+		opts.SetTLSConfig(cfg.tlsConfig)
+	}
+	opts.SetDefaultPublishHandler(messageHandler)
 	opts.OnConnect = connectHandler
 	opts.OnConnectionLost = connectLostHandler
 	client := mqtt.NewClient(opts)
@@ -0,0 +1,176 @@
+package mqtt
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/eclipse/paho.golang/autopaho"
+	"github.com/eclipse/paho.golang/paho"
+)
+
+const mqttMessageExpiry = "MQTT_MESSAGE_EXPIRY"
+
+// connectTimeout is how long the first connection to the broker may take, and how long
+// a publication waits for the connection to come back.
+const connectTimeout = 30 * time.Second
+
+// utf8Payload is the payload format indicator of the JSON content messages.
+var utf8Payload byte = 1
+
+type v5Transport struct {
+	conn *autopaho.ConnectionManager
+	// messageExpiry in seconds of the content messages, none when zero.
+	messageExpiry uint32
+	// publishTimeout bounds the wait for the connection and the acknowledgement of a publication.
+	publishTimeout time.Duration
+
+	mu     sync.Mutex
+	filter string
+}
+
+func newV5Transport(handler func(*Message)) (*v5Transport, error) {
+	cfg, err := clientConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	messageExpiry, err := messageExpiryFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	t := &v5Transport{messageExpiry: messageExpiry, publishTimeout: connectTimeout}
+
+	brokerURL, err := url.Parse(cfg.brokerURL)
+	if err != nil {
+		return nil, fmt.Errorf("%s is invalid: %w", mqttBrokerURL, err)
+	}
+
+	pahoConfig := autopaho.ClientConfig{
+		BrokerUrls:     []*url.URL{brokerURL},
+		TlsCfg:         cfg.tlsConfig,
+		KeepAlive:      30,
+		OnConnectionUp: t.onConnectionUp,
+		OnConnectError: func(err error) {
+			log.Printf("Failed to connect to the MQTT broker: %v", err)
+		},
+		ClientConfig: paho.ClientConfig{
+			ClientID: cfg.clientID,
+			Router: paho.NewSingleHandlerRouter(func(p *paho.Publish) {
+				msg := &Message{Topic: p.Topic, Payload: p.Payload}
+				if p.Properties != nil {
+					msg.CorrelationData = p.Properties.CorrelationData
+				}
+				handler(msg)
+			}),
+			OnClientError: func(err error) {
+				fmt.Printf("Connect lost: %v", err)
+			},
+		},
+	}
+	if len(cfg.username) > 0 {
+		pahoConfig.SetUsernamePassword(cfg.username, []byte(cfg.password))
+	}
+
+	t.conn, err = autopaho.NewConnection(context.Background(), pahoConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
+	defer cancel()
+	if err := t.conn.AwaitConnection(ctx); err != nil {
+		return nil, fmt.Errorf("failed to connect to the MQTT broker: %w", err)
+	}
+
+	return t, nil
+}
+
+// messageExpiryFromEnv returns the expiry in seconds set by MQTT_MESSAGE_EXPIRY, zero when unset.
+func messageExpiryFromEnv() (uint32, error) {
+	value := envOrDefault(mqttMessageExpiry, "")
+	if len(value) == 0 {
+		return 0, nil
+	}
+
+	expiry, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a duration: %w", mqttMessageExpiry, err)
+	}
+	if expiry < time.Second || expiry.Seconds() > math.MaxUint32 {
+		return 0, fmt.Errorf("%s must be between 1s and %ds", mqttMessageExpiry, uint32(math.MaxUint32))
+	}
+	return uint32(expiry.Seconds()), nil
+}
+
+// onConnectionUp subscribes again on every connection, the sessions start clean.
+func (t *v5Transport) onConnectionUp(conn *autopaho.ConnectionManager, _ *paho.Connack) {
+	fmt.Println("MQTT Connected")
+
+	t.mu.Lock()
+	filter := t.filter
+	t.mu.Unlock()
+
+	if len(filter) > 0 {
+		if err := t.subscribe(conn, filter); err != nil {
+			log.Printf("Failed to subscribe to %s: %v", filter, err)
+		}
+	}
+}
+
+func (t *v5Transport) Publish(p *publication) error {
+	// wait a while for the connection to come back rather than dropping the message,
+	// without blocking the sender forever while the broker is unreachable
+	ctx, cancel := context.WithTimeout(context.Background(), t.publishTimeout)
+	defer cancel()
+	if err := t.conn.AwaitConnection(ctx); err != nil {
+		return fmt.Errorf("not connected to the MQTT broker: %w", err)
+	}
+
+	_, err := t.conn.Publish(ctx, &paho.Publish{
+		QoS:        1,
+		Topic:      p.topic,
+		Payload:    p.payload,
+		Properties: t.publishProperties(p),
+	})
+	return err
+}
+
+// publishProperties returns the MQTT 5 properties of the message of p.
+func (t *v5Transport) publishProperties(p *publication) *paho.PublishProperties {
+	properties := &paho.PublishProperties{
+		ContentType:     "application/json",
+		PayloadFormat:   &utf8Payload,
+		ResponseTopic:   p.responseTopic,
+		CorrelationData: p.correlationData,
+	}
+	if t.messageExpiry > 0 {
+		properties.MessageExpiry = &t.messageExpiry
+	}
+	for _, property := range p.properties {
+		properties.User.Add(property[0], property[1])
+	}
+	return properties
+}
+
+func (t *v5Transport) Subscribe(filter string) error {
+	t.mu.Lock()
+	t.filter = filter
+	t.mu.Unlock()
+
+	return t.subscribe(t.conn, filter)
+}
+
+func (t *v5Transport) subscribe(conn *autopaho.ConnectionManager, filter string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
+	defer cancel()
+
+	_, err := conn.Subscribe(ctx, &paho.Subscribe{
+		Subscriptions: []paho.SubscribeOptions{{Topic: filter, QoS: 1}},
+	})
+	return err
+}
@@ -0,0 +1,186 @@
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.golang/autopaho"
+	"github.com/eclipse/paho.golang/paho"
+	"github.com/kube-orchestra/maestro/internal/db"
+)
+
+func TestMessageExpiryFromEnv(t *testing.T) {
+	for _, tc := range []struct {
+		value string
+		want  uint32
+		valid bool
+	}{
+		{value: "", want: 0, valid: true},
+		{value: "90s", want: 90, valid: true},
+		{value: "1h", want: 3600, valid: true},
+		{value: "500ms", valid: false},
+		{value: "-1m", valid: false},
+		{value: "1200000h", valid: false},
+		{value: "60", valid: false},
+	} {
+		t.Run(tc.value, func(t *testing.T) {
+			t.Setenv(mqttMessageExpiry, tc.value)
+			got, err := messageExpiryFromEnv()
+			if !tc.valid {
+				if err == nil {
+					t.Fatalf("accepted %q", tc.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Errorf("got expiry %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func testPublication(t *testing.T, msg *db.ResourceMessage) *publication {
+	t.Helper()
+
+	topics, err := TopicsFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Connection{topics: topics}
+	return c.publication(msg)
+}
+
+func TestPublishProperties(t *testing.T) {
+	msg := &db.ResourceMessage{Id: "r1", ConsumerId: "c1", DeletionTimestamp: 1700000001}
+	msg.ResourceGenerationID = 3
+	msg.SentTimestamp = 1700000000
+	p := testPublication(t, msg)
+
+	tr := &v5Transport{messageExpiry: 60}
+	properties := tr.publishProperties(p)
+
+	if properties.ResponseTopic != "v1/c1/r1/status" {
+		t.Errorf("got response topic %q, want the status topic of the resource", properties.ResponseTopic)
+	}
+	if string(properties.CorrelationData) != "3" {
+		t.Errorf("got correlation data %q, want the generation 3", properties.CorrelationData)
+	}
+	if properties.MessageExpiry == nil || *properties.MessageExpiry != 60 {
+		t.Errorf("got message expiry %v, want 60", properties.MessageExpiry)
+	}
+	if properties.ContentType != "application/json" || properties.PayloadFormat == nil || *properties.PayloadFormat != 1 {
+		t.Errorf("got content type %q and payload format %v, want UTF-8 JSON", properties.ContentType, properties.PayloadFormat)
+	}
+
+	want := map[string]string{
+		"consumerId":           "c1",
+		"resourceId":           "r1",
+		"resourceGenerationID": "3",
+		"sentTimestamp":        "1700000000",
+		"deletionTimestamp":    "1700000001",
+	}
+	if len(properties.User) != len(want) {
+		t.Errorf("got user properties %v, want %v", properties.User, want)
+	}
+	for key, value := range want {
+		if got := properties.User.Get(key); got != value {
+			t.Errorf("got user property %s=%q, want %q", key, got, value)
+		}
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(p.payload, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload["resourceGenerationID"] != float64(3) {
+		t.Errorf("got payload generation %v, want 3", payload["resourceGenerationID"])
+	}
+}
+
+func TestPublishPropertiesWithoutExpiry(t *testing.T) {
+	msg := &db.ResourceMessage{Id: "r1", ConsumerId: "c1"}
+	msg.ResourceGenerationID = 1
+	properties := (&v5Transport{}).publishProperties(testPublication(t, msg))
+
+	if properties.MessageExpiry != nil {
+		t.Errorf("got message expiry %d, want none", *properties.MessageExpiry)
+	}
+	if got := properties.User.Get("deletionTimestamp"); got != "" {
+		t.Errorf("got deletion timestamp %q on a message not deleting its resource", got)
+	}
+}
+
+func TestWithCorrelatedGeneration(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		status          string
+		correlationData string
+		wantGeneration  int64
+		wantReason      string
+	}{
+		{name: "no correlation data", status: `{"resourceGenerationID": 2}`, wantGeneration: 2},
+		{name: "generation filled in", status: `{}`, correlationData: "2", wantGeneration: 2},
+		{name: "same generation", status: `{"resourceGenerationID": 2}`, correlationData: "2", wantGeneration: 2},
+		{name: "other generation", status: `{"resourceGenerationID": 1}`, correlationData: "2", wantReason: RejectCorrelationMismatch},
+		{name: "invalid correlation data", status: `{}`, correlationData: "two", wantReason: RejectCorrelationMismatch},
+		{name: "invalid payload", status: `{`, correlationData: "2", wantReason: RejectInvalidPayload},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			payload, err := withCorrelatedGeneration([]byte(tc.status), []byte(tc.correlationData))
+			if len(tc.wantReason) > 0 {
+				var r *rejection
+				if !errors.As(err, &r) || r.reason != tc.wantReason {
+					t.Fatalf("got error %v, want a %s rejection", err, tc.wantReason)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var status db.StatusMessage
+			if err := json.Unmarshal(payload, &status); err != nil {
+				t.Fatal(err)
+			}
+			if status.ResourceGenerationID != tc.wantGeneration {
+				t.Errorf("got generation %d, want %d", status.ResourceGenerationID, tc.wantGeneration)
+			}
+		})
+	}
+}
+
+func TestPublishGivesUpWithoutConnection(t *testing.T) {
+	// nothing listens on the port, the connection never comes up
+	brokerURL, _ := url.Parse("tcp://127.0.0.1:1")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	conn, err := autopaho.NewConnection(ctx, autopaho.ClientConfig{
+		BrokerUrls:        []*url.URL{brokerURL},
+		ConnectRetryDelay: 10 * time.Millisecond,
+		ClientConfig:      paho.ClientConfig{ClientID: "test"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tr := &v5Transport{conn: conn, publishTimeout: 100 * time.Millisecond}
+	done := make(chan error, 1)
+	go func() {
+		done <- tr.Publish(&publication{topic: "v1/c1/r1/content", payload: []byte("{}")})
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("got error %v, want the deadline of the publication", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("publication still waiting for the connection")
+	}
+}
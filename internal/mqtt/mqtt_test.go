@@ -0,0 +1,116 @@
+package mqtt
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kube-orchestra/maestro/internal/db"
+	v1 "github.com/kube-orchestra/maestro/proto/api/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// newTestConnection returns a connection ingesting the status messages into store, without a broker.
+func newTestConnection(t *testing.T, store db.Store) *Connection {
+	t.Helper()
+
+	topics, err := TopicsFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Connection{store: store, topics: topics, deadLetterRetention: time.Hour}
+}
+
+func putTestResource(t *testing.T, store db.Store, id, consumerID string, generationID, deletionTimestamp int64) {
+	t.Helper()
+
+	r := &db.Resource{
+		Id:                   id,
+		ConsumerId:           consumerID,
+		ResourceGenerationID: generationID,
+		DeletionTimestamp:    deletionTimestamp,
+		Object: unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]interface{}{"name": id},
+		}},
+	}
+	if err := store.CreateResource(r); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func statusMessage(consumerID, resourceID string, generationID int64, conditions string) *Message {
+	return &Message{
+		Topic: "v1/" + consumerID + "/" + resourceID + "/status",
+		Payload: []byte(fmt.Sprintf(`{"resourceGenerationID": %d, "reconcileStatus": {"conditions": [%s]}}`,
+			generationID, conditions)),
+	}
+}
+
+const deletedCondition = `{"type": "Deleted", "status": "True", "reason": "Deleted", "message": "", "lastTransitionTime": "2023-01-01T00:00:00Z"}`
+
+func TestCascadeDeletionRemovesConsumerWithLastResource(t *testing.T) {
+	store := db.NewMemoryStore()
+	c := newTestConnection(t, store)
+
+	if err := store.CreateConsumer(&v1.Consumer{Id: "c1", DeletionTimestamp: 1}); err != nil {
+		t.Fatal(err)
+	}
+	putTestResource(t, store, "r1", "c1", 2, 1)
+	putTestResource(t, store, "r2", "c1", 2, 1)
+
+	c.messagePubHandler(statusMessage("c1", "r1", 2, deletedCondition))
+	if _, err := store.GetResource("r1"); err == nil {
+		t.Fatal("resource r1 not deleted once the agent reported it deleted")
+	}
+	if _, err := store.GetConsumer("c1"); err != nil {
+		t.Fatalf("consumer removed before its last resource: %v", err)
+	}
+
+	c.messagePubHandler(statusMessage("c1", "r2", 2, deletedCondition))
+	if _, err := store.GetConsumer("c1"); err == nil {
+		t.Fatal("consumer kept after the deletion of its last resource")
+	}
+}
+
+func TestDeletionKeepsConsumerNotBeingDeleted(t *testing.T) {
+	store := db.NewMemoryStore()
+	c := newTestConnection(t, store)
+
+	if err := store.CreateConsumer(&v1.Consumer{Id: "c1"}); err != nil {
+		t.Fatal(err)
+	}
+	putTestResource(t, store, "r1", "c1", 2, 1)
+
+	c.messagePubHandler(statusMessage("c1", "r1", 2, deletedCondition))
+	if _, err := store.GetResource("r1"); err == nil {
+		t.Fatal("resource r1 not deleted once the agent reported it deleted")
+	}
+	if _, err := store.GetConsumer("c1"); err != nil {
+		t.Fatalf("consumer removed with its last resource without a CASCADE deletion: %v", err)
+	}
+}
+
+func TestDeadLettersListedInReceivedOrder(t *testing.T) {
+	store := db.NewMemoryStore()
+	c := newTestConnection(t, store)
+
+	for i := 0; i < 50; i++ {
+		c.messagePubHandler(&Message{Topic: "invalid", Payload: []byte(fmt.Sprint(i))})
+	}
+
+	deadLetters, err := store.ListDeadLetters(db.DeadLetterListOptions{Limit: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deadLetters) != 50 {
+		t.Fatalf("got %d dead letters, want 50", len(deadLetters))
+	}
+	for i, d := range deadLetters {
+		if string(d.Payload) != fmt.Sprint(i) || d.Reason != RejectInvalidTopic {
+			t.Fatalf("dead letter %d is message %s rejected for %s, want message %d rejected for %s",
+				i, d.Payload, d.Reason, i, RejectInvalidTopic)
+		}
+	}
+}
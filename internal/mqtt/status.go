@@ -0,0 +1,170 @@
+package mqtt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"io"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kube-orchestra/maestro/internal/db"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// Reasons status messages are rejected for.
+const (
+	RejectInvalidTopic        = "INVALID_TOPIC"
+	RejectInvalidPayload      = "INVALID_PAYLOAD"
+	RejectCorrelationMismatch = "CORRELATION_MISMATCH"
+	RejectUnknownResource     = "UNKNOWN_RESOURCE"
+	RejectConsumerMismatch    = "CONSUMER_MISMATCH"
+	RejectUnknownGeneration   = "UNKNOWN_GENERATION"
+	RejectStoreError          = "STORE_ERROR"
+	RejectInternalError       = "INTERNAL_ERROR"
+)
+
+// maxDeadLetterPayload is the size the payloads of the dead letters are truncated to.
+const maxDeadLetterPayload = 64 << 10
+
+// deadLetterCleanupInterval is the period at which the expired dead letters are deleted.
+const deadLetterCleanupInterval = 10 * time.Minute
+
+var (
+	// statusMessages counts the status messages received, ingested, ignored as stale and rejected.
+	statusMessages = expvar.NewMap("mqtt_status_messages")
+	// statusRejects counts the status messages rejected by reason.
+	statusRejects = expvar.NewMap("mqtt_status_rejects")
+)
+
+var conditionStatuses = sets.New(string(metav1.ConditionTrue), string(metav1.ConditionFalse), string(metav1.ConditionUnknown))
+
+// rejection is the reason a status message was not ingested.
+type rejection struct {
+	reason string
+	err    error
+}
+
+func (r *rejection) Error() string {
+	return r.err.Error()
+}
+
+func (r *rejection) Unwrap() error {
+	return r.err
+}
+
+func reject(reason string, err error) error {
+	return &rejection{reason: reason, err: err}
+}
+
+// decodeStatus decodes the status message of statusData, rejecting values of the wrong type and invalid
+// or missing values. Unknown fields are ignored, for agents newer than maestro to report more.
+func decodeStatus(statusData []byte) (*db.StatusMessage, error) {
+	decoder := json.NewDecoder(bytes.NewReader(statusData))
+
+	var status db.StatusMessage
+	if err := decoder.Decode(&status); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after the status message")
+	}
+
+	if allErrs := validateStatus(&status); len(allErrs) > 0 {
+		return nil, allErrs.ToAggregate()
+	}
+	return &status, nil
+}
+
+func validateStatus(status *db.StatusMessage) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if status.ResourceGenerationID <= 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("resourceGenerationID"), status.ResourceGenerationID,
+			"must be the generation of the content the status is for"))
+	}
+	if status.SentTimestamp < 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("sentTimestamp"), status.SentTimestamp, "must not be negative"))
+	}
+
+	fldPath := field.NewPath("reconcileStatus")
+	reconcileStatus := status.ReconcileStatus
+	if reconcileStatus.ObservedGeneration < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("observedGeneration"), reconcileStatus.ObservedGeneration,
+			"must not be negative"))
+	}
+	if reconcileStatus.CreationTimestamp != "" {
+		if _, err := time.Parse(time.RFC3339, reconcileStatus.CreationTimestamp); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("creationTimestamp"), reconcileStatus.CreationTimestamp,
+				"must be an RFC 3339 timestamp"))
+		}
+	}
+
+	types := sets.New[string]()
+	for i, c := range reconcileStatus.Conditions {
+		idxPath := fldPath.Child("conditions").Index(i)
+		switch {
+		case c.Type == "":
+			allErrs = append(allErrs, field.Required(idxPath.Child("type"), ""))
+		case types.Has(c.Type):
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("type"), c.Type))
+		}
+		types.Insert(c.Type)
+
+		if !conditionStatuses.Has(string(c.Status)) {
+			allErrs = append(allErrs, field.NotSupported(idxPath.Child("status"), c.Status, sets.List(conditionStatuses)))
+		}
+	}
+
+	return allErrs
+}
+
+// deadLetter records msg as rejected by err.
+func (c *Connection) deadLetter(msg *Message, consumerID, resourceID string, err error) {
+	reason := RejectInternalError
+	var r *rejection
+	if errors.As(err, &r) {
+		reason = r.reason
+	}
+
+	log.Printf("Rejected status message on topic %s, %s: %v", msg.Topic, reason, err)
+	statusMessages.Add("rejected", 1)
+	statusRejects.Add(reason, 1)
+
+	payload := msg.Payload
+	if len(payload) > maxDeadLetterPayload {
+		payload = payload[:maxDeadLetterPayload]
+	}
+
+	// the IDs are ordered by time, the dead letters are listed in the order they were received
+	now := time.Now()
+	d := &db.DeadLetter{
+		Id:                  uuid.Must(uuid.NewV7()).String(),
+		Topic:               msg.Topic,
+		ConsumerId:          consumerID,
+		ResourceId:          resourceID,
+		Payload:             payload,
+		Reason:              reason,
+		Error:               err.Error(),
+		ReceivedTimestamp:   now.Unix(),
+		ExpirationTimestamp: now.Add(c.deadLetterRetention).Unix(),
+	}
+	if err := c.store.PutDeadLetter(d); err != nil {
+		log.Printf("Failed to store the dead letter of the status message on topic %s: %v", msg.Topic, err)
+	}
+}
+
+// StartDeadLetterCleanup periodically deletes the expired dead letters.
+func (c *Connection) StartDeadLetterCleanup() {
+	go func() {
+		for range time.Tick(deadLetterCleanupInterval) {
+			if err := c.store.DeleteExpiredDeadLetters(time.Now().Unix()); err != nil {
+				log.Printf("Failed to delete the expired dead letters: %v", err)
+			}
+		}
+	}()
+}
@@ -0,0 +1,61 @@
+package mqtt
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDecodeStatus(t *testing.T) {
+	status, err := decodeStatus([]byte(`{
+		"resourceGenerationID": 2,
+		"sentTimestamp": 1700000000,
+		"reconcileStatus": {
+			"observedGeneration": 3,
+			"creationTimestamp": "2023-01-01T00:00:00Z",
+			"conditions": [{"type": "Applied", "status": "True", "reason": "Applied", "message": "", "lastTransitionTime": "2023-01-01T00:00:00Z"}]
+		},
+		"contentStatus": {"status": {"replicas": 1}},
+		"agentVersion": "v2"
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.ResourceGenerationID != 2 || status.ReconcileStatus.ObservedGeneration != 3 ||
+		len(status.ReconcileStatus.Conditions) != 1 || status.ReconcileStatus.Conditions[0].Status != metav1.ConditionTrue ||
+		status.ContentStatus["status"] == nil {
+		t.Errorf("decoded status %+v", status)
+	}
+
+	tests := []struct {
+		name    string
+		payload string
+		want    string
+	}{
+		{"not JSON", `status`, "invalid character"},
+		{"wrong generation type", `{"resourceGenerationID": "1"}`, "resourceGenerationID"},
+		{"wrong conditions type", `{"resourceGenerationID": 1, "reconcileStatus": {"conditions": {}}}`, "reconcileStatus.conditions"},
+		{"wrong content status type", `{"resourceGenerationID": 1, "contentStatus": []}`, "contentStatus"},
+		{"trailing data", `{"resourceGenerationID": 1} {}`, "unexpected data"},
+		{"missing generation", `{}`, "resourceGenerationID"},
+		{"negative sent timestamp", `{"resourceGenerationID": 1, "sentTimestamp": -1}`, "sentTimestamp"},
+		{"negative observed generation", `{"resourceGenerationID": 1, "reconcileStatus": {"observedGeneration": -1}}`,
+			"reconcileStatus.observedGeneration"},
+		{"invalid creation timestamp", `{"resourceGenerationID": 1, "reconcileStatus": {"creationTimestamp": "yesterday"}}`,
+			"reconcileStatus.creationTimestamp"},
+		{"condition without type", `{"resourceGenerationID": 1, "reconcileStatus": {"conditions": [{"status": "True"}]}}`,
+			"reconcileStatus.conditions[0].type"},
+		{"duplicate condition", `{"resourceGenerationID": 1, "reconcileStatus": {"conditions": [
+			{"type": "Applied", "status": "True"}, {"type": "Applied", "status": "False"}]}}`,
+			"reconcileStatus.conditions[1].type"},
+		{"invalid condition status", `{"resourceGenerationID": 1, "reconcileStatus": {"conditions": [{"type": "Applied", "status": "Yes"}]}}`,
+			"reconcileStatus.conditions[0].status"},
+	}
+	for _, tt := range tests {
+		_, err := decodeStatus([]byte(tt.payload))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: got error %v, want one mentioning %q", tt.name, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package mqtt
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	mqttCAFile             = "MQTT_CA_FILE"
+	mqttClientCertFile     = "MQTT_CLIENT_CERT_FILE"
+	mqttClientKeyFile      = "MQTT_CLIENT_KEY_FILE"
+	mqttServerName         = "MQTT_SERVER_NAME"
+	mqttInsecureSkipVerify = "MQTT_INSECURE_SKIP_VERIFY"
+)
+
+// tlsSchemes are the broker URL schemes the MQTT client connects over TLS with.
+var tlsSchemes = map[string]bool{"ssl": true, "tls": true, "mqtts": true, "mqtt+ssl": true, "tcps": true, "wss": true}
+
+// TLSConfigFromEnv returns the TLS configuration of the connection to the broker of brokerURL,
+// nil when none of MQTT_CA_FILE, MQTT_CLIENT_CERT_FILE, MQTT_CLIENT_KEY_FILE, MQTT_SERVER_NAME
+// and MQTT_INSECURE_SKIP_VERIFY is set. The CA bundle and the client certificate are reloaded
+// when their files change, rotated certificates are used from the next connection on.
+func TLSConfigFromEnv(brokerURL string) (*tls.Config, error) {
+	caFile := os.Getenv(mqttCAFile)
+	certFile := os.Getenv(mqttClientCertFile)
+	keyFile := os.Getenv(mqttClientKeyFile)
+	serverName := os.Getenv(mqttServerName)
+
+	insecureSkipVerify := false
+	if value := os.Getenv(mqttInsecureSkipVerify); len(value) > 0 {
+		var err error
+		if insecureSkipVerify, err = strconv.ParseBool(value); err != nil {
+			return nil, fmt.Errorf("%s must be a boolean: %w", mqttInsecureSkipVerify, err)
+		}
+	}
+
+	if caFile == "" && certFile == "" && keyFile == "" && serverName == "" && !insecureSkipVerify {
+		return nil, nil
+	}
+
+	u, err := url.Parse(brokerURL)
+	if err != nil {
+		return nil, fmt.Errorf("%s is invalid: %w", mqttBrokerURL, err)
+	}
+	if !tlsSchemes[u.Scheme] {
+		return nil, fmt.Errorf("%s must use a TLS scheme, e.g. tls://, when TLS is configured", mqttBrokerURL)
+	}
+
+	if (certFile == "") != (keyFile == "") {
+		return nil, fmt.Errorf("%s and %s must be set together", mqttClientCertFile, mqttClientKeyFile)
+	}
+
+	certs := &certificates{caFile: caFile, certFile: certFile, keyFile: keyFile}
+	if err := certs.reload(); err != nil {
+		return nil, err
+	}
+
+	config := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         serverName,
+		InsecureSkipVerify: insecureSkipVerify,
+	}
+
+	if certFile != "" {
+		config.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			_, cert := certs.current()
+			return cert, nil
+		}
+	}
+
+	if caFile != "" && !insecureSkipVerify {
+		// the name requested by the client is empty when the broker is addressed by IP,
+		// verify against the expected name rather than skipping the name check
+		name := serverName
+		if name == "" {
+			name = u.Hostname()
+		}
+		if name == "" {
+			return nil, fmt.Errorf("%s must be set when %s has no host", mqttServerName, mqttBrokerURL)
+		}
+
+		// the roots of the standard verification are fixed, verify against the current CA bundle instead
+		config.InsecureSkipVerify = true
+		config.VerifyConnection = func(cs tls.ConnectionState) error {
+			roots, _ := certs.current()
+			return verifyServer(cs, roots, name)
+		}
+	}
+
+	return config, nil
+}
+
+// verifyServer verifies the certificate chain of the server of cs against roots, and that it is
+// issued for name, a host name or an IP address.
+func verifyServer(cs tls.ConnectionState, roots *x509.CertPool, name string) error {
+	if len(cs.PeerCertificates) == 0 {
+		return fmt.Errorf("the MQTT broker sent no certificate")
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range cs.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err := cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+		DNSName:       name,
+		Roots:         roots,
+		Intermediates: intermediates,
+	})
+	return err
+}
+
+// certificates holds the CA bundle and the client certificate loaded from their files.
+type certificates struct {
+	caFile   string
+	certFile string
+	keyFile  string
+
+	mu       sync.Mutex
+	modTimes [3]time.Time
+	roots    *x509.CertPool
+	cert     *tls.Certificate
+}
+
+// current returns the CA bundle and the client certificate, reloaded first when their files changed.
+// Files that fail to load, e.g. while they are being rewritten, leave the previous ones in use.
+func (c *certificates) current() (*x509.CertPool, *tls.Certificate) {
+	if err := c.reload(); err != nil {
+		log.Printf("Failed to reload the MQTT TLS certificates, using the previous ones: %v", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cert := c.cert
+	if cert == nil {
+		// no client certificate is sent
+		cert = &tls.Certificate{}
+	}
+	return c.roots, cert
+}
+
+// reload loads the files that changed since they were last loaded.
+func (c *certificates) reload() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var modTimes [3]time.Time
+	for i, file := range []string{c.caFile, c.certFile, c.keyFile} {
+		if file == "" {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+		modTimes[i] = info.ModTime()
+	}
+	if modTimes == c.modTimes {
+		return nil
+	}
+
+	roots := c.roots
+	if c.caFile != "" && !modTimes[0].Equal(c.modTimes[0]) {
+		pem, err := os.ReadFile(c.caFile)
+		if err != nil {
+			return err
+		}
+		roots = x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("%s has no PEM encoded certificate", c.caFile)
+		}
+	}
+
+	cert := c.cert
+	if c.certFile != "" && (!modTimes[1].Equal(c.modTimes[1]) || !modTimes[2].Equal(c.modTimes[2])) {
+		loaded, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+		if err != nil {
+			return err
+		}
+		cert = &loaded
+	}
+
+	c.modTimes, c.roots, c.cert = modTimes, roots, cert
+	return nil
+}
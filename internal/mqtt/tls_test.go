@@ -0,0 +1,326 @@
+package mqtt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/eclipse/paho.mqtt.golang/packets"
+)
+
+// testCA is a certificate authority issuing the certificates of a test.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T, name string) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+func (ca *testCA) pool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	return pool
+}
+
+// issue returns the PEM encoded certificate and key of name, valid for the DNS names and IP addresses of hosts.
+func (ca *testCA) issue(t *testing.T, name string, usage x509.ExtKeyUsage, hosts ...string) ([]byte, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func (ca *testCA) serverCert(t *testing.T, hosts ...string) tls.Certificate {
+	t.Helper()
+
+	certPEM, keyPEM := ca.issue(t, "broker", x509.ExtKeyUsageServerAuth, hosts...)
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+// testBroker is an in-process MQTT broker over mutual TLS, answering every CONNECT with a CONNACK.
+type testBroker struct {
+	url string
+
+	mu        sync.Mutex
+	cert      tls.Certificate
+	clientCAs *x509.CertPool
+}
+
+func newTestBroker(t *testing.T, cert tls.Certificate, clientCAs *x509.CertPool) *testBroker {
+	t.Helper()
+
+	b := &testBroker{cert: cert, clientCAs: clientCAs}
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			return &tls.Config{
+				Certificates: []tls.Certificate{b.cert},
+				ClientAuth:   tls.RequireAndVerifyClientCert,
+				ClientCAs:    b.clientCAs,
+			}, nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go b.serve(conn)
+		}
+	}()
+
+	b.url = "tls://" + listener.Addr().String()
+	return b
+}
+
+func (b *testBroker) set(cert tls.Certificate, clientCAs *x509.CertPool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.cert, b.clientCAs = cert, clientCAs
+}
+
+func (b *testBroker) serve(conn net.Conn) {
+	defer conn.Close()
+
+	if _, err := packets.ReadPacket(conn); err != nil {
+		return
+	}
+	if err := packets.NewControlPacket(packets.Connack).Write(conn); err != nil {
+		return
+	}
+	// wait for the client to disconnect
+	for {
+		if _, err := packets.ReadPacket(conn); err != nil {
+			return
+		}
+	}
+}
+
+// connect connects to the broker of brokerURL with config and disconnects.
+func connect(brokerURL string, config *tls.Config) error {
+	opts := mqtt.NewClientOptions().
+		AddBroker(brokerURL).
+		SetClientID("maestro-test").
+		SetTLSConfig(config).
+		SetAutoReconnect(false).
+		SetConnectTimeout(5 * time.Second)
+
+	client := mqtt.NewClient(opts)
+	token := client.Connect()
+	if !token.WaitTimeout(10 * time.Second) {
+		return os.ErrDeadlineExceeded
+	}
+	if err := token.Error(); err != nil {
+		return err
+	}
+	client.Disconnect(0)
+	return nil
+}
+
+// writeFile writes data to path with a modification time in the future, the reload
+// only notices files whose modification time changed.
+func writeFile(t *testing.T, path string, data []byte, modTime time.Time) {
+	t.Helper()
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// tlsTestFiles sets the TLS environment to the CA bundle and the client certificate of the files returned.
+func tlsTestFiles(t *testing.T, serverCA, clientCA *testCA) (caFile, certFile, keyFile string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	caFile = filepath.Join(dir, "ca.crt")
+	certFile = filepath.Join(dir, "client.crt")
+	keyFile = filepath.Join(dir, "client.key")
+
+	certPEM, keyPEM := clientCA.issue(t, "maestro", x509.ExtKeyUsageClientAuth)
+	now := time.Now()
+	writeFile(t, caFile, serverCA.pem, now)
+	writeFile(t, certFile, certPEM, now)
+	writeFile(t, keyFile, keyPEM, now)
+
+	t.Setenv(mqttCAFile, caFile)
+	t.Setenv(mqttClientCertFile, certFile)
+	t.Setenv(mqttClientKeyFile, keyFile)
+	return caFile, certFile, keyFile
+}
+
+func TestTLSMutualAuthentication(t *testing.T) {
+	serverCA := newTestCA(t, "server-ca")
+	clientCA := newTestCA(t, "client-ca")
+	broker := newTestBroker(t, serverCA.serverCert(t, "127.0.0.1"), clientCA.pool())
+	tlsTestFiles(t, serverCA, clientCA)
+
+	config, err := TLSConfigFromEnv(broker.url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := connect(broker.url, config); err != nil {
+		t.Fatalf("failed to connect with a client certificate: %v", err)
+	}
+
+	t.Setenv(mqttClientCertFile, "")
+	t.Setenv(mqttClientKeyFile, "")
+	config, err = TLSConfigFromEnv(broker.url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := connect(broker.url, config); err == nil {
+		t.Fatal("connected without the client certificate required by the broker")
+	}
+}
+
+func TestTLSRejectsWrongHostname(t *testing.T) {
+	serverCA := newTestCA(t, "server-ca")
+	clientCA := newTestCA(t, "client-ca")
+	// the broker is addressed by IP, its certificate is only valid for another host
+	broker := newTestBroker(t, serverCA.serverCert(t, "broker.example.com"), clientCA.pool())
+	tlsTestFiles(t, serverCA, clientCA)
+
+	config, err := TLSConfigFromEnv(broker.url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := connect(broker.url, config); err == nil {
+		t.Fatal("connected to a broker whose certificate does not match its address")
+	}
+
+	t.Setenv(mqttServerName, "other.example.com")
+	config, err = TLSConfigFromEnv(broker.url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := connect(broker.url, config); err == nil {
+		t.Fatal("connected to a broker whose certificate does not match MQTT_SERVER_NAME")
+	}
+
+	t.Setenv(mqttServerName, "broker.example.com")
+	config, err = TLSConfigFromEnv(broker.url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := connect(broker.url, config); err != nil {
+		t.Fatalf("failed to connect with the name of the broker certificate: %v", err)
+	}
+}
+
+func TestTLSReload(t *testing.T) {
+	serverCA := newTestCA(t, "server-ca")
+	clientCA := newTestCA(t, "client-ca")
+	broker := newTestBroker(t, serverCA.serverCert(t, "127.0.0.1"), clientCA.pool())
+	caFile, certFile, keyFile := tlsTestFiles(t, serverCA, clientCA)
+
+	// a single configuration is used for every connection, as by the reconnecting client
+	config, err := TLSConfigFromEnv(broker.url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := connect(broker.url, config); err != nil {
+		t.Fatal(err)
+	}
+
+	// rotate both authorities on the broker, the files still hold the previous certificates
+	newServerCA := newTestCA(t, "new-server-ca")
+	newClientCA := newTestCA(t, "new-client-ca")
+	broker.set(newServerCA.serverCert(t, "127.0.0.1"), newClientCA.pool())
+	if err := connect(broker.url, config); err == nil {
+		t.Fatal("connected with the certificates of the previous authorities")
+	}
+
+	modTime := time.Now().Add(time.Minute)
+	certPEM, keyPEM := newClientCA.issue(t, "maestro", x509.ExtKeyUsageClientAuth)
+	writeFile(t, caFile, newServerCA.pem, modTime)
+	writeFile(t, certFile, certPEM, modTime)
+	writeFile(t, keyFile, keyPEM, modTime)
+	if err := connect(broker.url, config); err != nil {
+		t.Fatalf("failed to connect with the rotated certificates: %v", err)
+	}
+
+	// a file being rewritten leaves the previous certificates in use
+	writeFile(t, caFile, []byte("garbage"), modTime.Add(time.Minute))
+	if err := connect(broker.url, config); err != nil {
+		t.Fatalf("failed to connect while the CA bundle is invalid: %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package mqtt
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	mqttTopicPrefix  = "MQTT_TOPIC_PREFIX"
+	mqttContentTopic = "MQTT_CONTENT_TOPIC"
+	mqttStatusTopic  = "MQTT_STATUS_TOPIC"
+)
+
+const (
+	defaultTopicPrefix  = "v1"
+	defaultContentTopic = "{prefix}/{consumer}/{resource}/content"
+	defaultStatusTopic  = "{prefix}/{consumer}/{resource}/status"
+)
+
+const (
+	prefixPlaceholder   = "{prefix}"
+	consumerPlaceholder = "{consumer}"
+	resourcePlaceholder = "{resource}"
+)
+
+// TopicTemplate is a layout of MQTT topics naming a resource of a consumer, e.g.
+// "v1/{consumer}/{resource}/content", where the placeholders are whole topic levels.
+type TopicTemplate struct {
+	levels []string
+	// indices of the consumer and resource levels
+	consumer int
+	resource int
+}
+
+// ParseTopicTemplate parses template after replacing its {prefix} placeholder with prefix.
+// The template must have exactly one {consumer} and one {resource} level.
+func ParseTopicTemplate(template, prefix string) (*TopicTemplate, error) {
+	expanded := strings.ReplaceAll(template, prefixPlaceholder, prefix)
+
+	t := &TopicTemplate{levels: strings.Split(expanded, "/"), consumer: -1, resource: -1}
+	for i, level := range t.levels {
+		switch {
+		case level == consumerPlaceholder && t.consumer == -1:
+			t.consumer = i
+		case level == resourcePlaceholder && t.resource == -1:
+			t.resource = i
+		case level == consumerPlaceholder || level == resourcePlaceholder:
+			return nil, fmt.Errorf("topic template %q has more than one %s level", template, level)
+		case level == "":
+			return nil, fmt.Errorf("topic template %q has an empty level", template)
+		case strings.ContainsAny(level, "{}"):
+			return nil, fmt.Errorf("topic template %q has an unexpected placeholder in level %q, "+
+				"placeholders are %s, %s and %s and must occupy whole levels",
+				template, level, prefixPlaceholder, consumerPlaceholder, resourcePlaceholder)
+		case strings.ContainsAny(level, "+#"):
+			return nil, fmt.Errorf("topic template %q must not contain wildcards", template)
+		}
+	}
+
+	if t.consumer == -1 || t.resource == -1 {
+		return nil, fmt.Errorf("topic template %q must have a %s and a %s level",
+			template, consumerPlaceholder, resourcePlaceholder)
+	}
+	return t, nil
+}
+
+// Topic returns the topic of the resource of the consumer.
+func (t *TopicTemplate) Topic(consumerID, resourceID string) string {
+	levels := append([]string{}, t.levels...)
+	levels[t.consumer] = consumerID
+	levels[t.resource] = resourceID
+	return strings.Join(levels, "/")
+}
+
+// Filter returns the topic filter matching the topics of all the resources.
+func (t *TopicTemplate) Filter() string {
+	return t.Topic("+", "+")
+}
+
+// Parse returns the consumer and resource IDs of topic, ok is false when topic does not follow the template.
+func (t *TopicTemplate) Parse(topic string) (consumerID, resourceID string, ok bool) {
+	levels := strings.Split(topic, "/")
+	if len(levels) != len(t.levels) {
+		return "", "", false
+	}
+
+	for i, level := range levels {
+		if i != t.consumer && i != t.resource && level != t.levels[i] {
+			return "", "", false
+		}
+	}
+
+	consumerID, resourceID = levels[t.consumer], levels[t.resource]
+	if consumerID == "" || resourceID == "" {
+		return "", "", false
+	}
+	return consumerID, resourceID, true
+}
+
+// Topics are the topics resources are sent and their status received on.
+type Topics struct {
+	Content *TopicTemplate
+	Status  *TopicTemplate
+}
+
+// TopicsFromEnv returns the topics set by MQTT_CONTENT_TOPIC and MQTT_STATUS_TOPIC,
+// their {prefix} set by MQTT_TOPIC_PREFIX.
+func TopicsFromEnv() (*Topics, error) {
+	prefix := envOrDefault(mqttTopicPrefix, defaultTopicPrefix)
+	if strings.ContainsAny(prefix, "+#{}") {
+		return nil, fmt.Errorf("%s must not contain wildcards or placeholders", mqttTopicPrefix)
+	}
+
+	content, err := ParseTopicTemplate(envOrDefault(mqttContentTopic, defaultContentTopic), prefix)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", mqttContentTopic, err)
+	}
+
+	status, err := ParseTopicTemplate(envOrDefault(mqttStatusTopic, defaultStatusTopic), prefix)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", mqttStatusTopic, err)
+	}
+
+	return &Topics{Content: content, Status: status}, nil
+}
+
+func envOrDefault(name, defaultValue string) string {
+	if value := os.Getenv(name); len(value) > 0 {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,74 @@
+package mqtt
+
+import (
+	"testing"
+)
+
+func TestParseTopicTemplate(t *testing.T) {
+	for _, template := range []string{
+		"{prefix}/{resource}/content",
+		"{prefix}/{consumer}/content",
+		"{prefix}/{consumer}/{consumer}/{resource}",
+		"{prefix}/{consumer}/{resource}/{resource}",
+		"{prefix}//{consumer}/{resource}",
+		"{prefix}/{consumer}/{resource}/",
+		"{prefix}/c-{consumer}/{resource}",
+		"{prefix}/{consumer}/{resource}/{other}",
+		"{prefix}/+/{consumer}/{resource}",
+		"{prefix}/{consumer}/{resource}/#",
+	} {
+		if _, err := ParseTopicTemplate(template, "v1"); err == nil {
+			t.Errorf("parsed invalid template %q", template)
+		}
+	}
+}
+
+func TestTopicTemplate(t *testing.T) {
+	template, err := ParseTopicTemplate("{prefix}/agents/{resource}/{consumer}/status", "maestro/v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := template.Topic("c1", "r1"), "maestro/v1/agents/r1/c1/status"; got != want {
+		t.Errorf("got topic %q, want %q", got, want)
+	}
+	if got, want := template.Filter(), "maestro/v1/agents/+/+/status"; got != want {
+		t.Errorf("got filter %q, want %q", got, want)
+	}
+
+	consumerID, resourceID, ok := template.Parse("maestro/v1/agents/r1/c1/status")
+	if !ok || consumerID != "c1" || resourceID != "r1" {
+		t.Errorf("parsed consumer %q, resource %q, ok %v, want c1, r1", consumerID, resourceID, ok)
+	}
+
+	for _, topic := range []string{
+		"maestro/v1/agents/r1/c1/content",
+		"maestro/v2/agents/r1/c1/status",
+		"maestro/v1/agents/r1/status",
+		"maestro/v1/agents/r1/c1/status/more",
+		"maestro/v1/agents//c1/status",
+		"maestro/v1/agents/r1//status",
+	} {
+		if consumerID, resourceID, ok := template.Parse(topic); ok {
+			t.Errorf("parsed consumer %q, resource %q of topic %q not following the template", consumerID, resourceID, topic)
+		}
+	}
+}
+
+func TestTopicsFromEnv(t *testing.T) {
+	topics, err := TopicsFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := topics.Content.Topic("c1", "r1"), "v1/c1/r1/content"; got != want {
+		t.Errorf("got default content topic %q, want %q", got, want)
+	}
+	if got, want := topics.Status.Filter(), "v1/+/+/status"; got != want {
+		t.Errorf("got default status filter %q, want %q", got, want)
+	}
+
+	t.Setenv(mqttTopicPrefix, "v1/+")
+	if _, err := TopicsFromEnv(); err == nil {
+		t.Error("accepted a prefix with a wildcard")
+	}
+}
@@ -0,0 +1,102 @@
+package mqtt
+
+import (
+	"fmt"
+	"strconv"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/kube-orchestra/maestro/internal/db"
+)
+
+const mqttProtocolVersion = "MQTT_PROTOCOL_VERSION"
+
+// Message is a message received from the broker.
+type Message struct {
+	Topic   string
+	Payload []byte
+	// Correlation data of the content message the status answers, only sent over MQTT 5.
+	CorrelationData []byte
+}
+
+// publication is a content message to publish.
+type publication struct {
+	topic   string
+	payload []byte
+	// The status replies are expected on responseTopic, carrying correlationData back.
+	responseTopic   string
+	correlationData []byte
+	// Metadata of the message as key value pairs.
+	properties [][2]string
+}
+
+// transport sends and receives the messages of a connection over a version of the MQTT protocol.
+// Only MQTT 5 carries the response topic, correlation data and properties of the publications.
+type transport interface {
+	Publish(p *publication) error
+	// Subscribe delivers the messages of the topics matching filter to the handler of the transport.
+	Subscribe(filter string) error
+}
+
+// newTransport connects to the broker with the MQTT version set by MQTT_PROTOCOL_VERSION,
+// 3 (3.1.1, the default) or 5. The received messages are passed to handler.
+func newTransport(handler func(*Message)) (transport, error) {
+	switch version := envOrDefault(mqttProtocolVersion, "3"); version {
+	case "3":
+		return newV3Transport(handler)
+	case "5":
+		return newV5Transport(handler)
+	default:
+		return nil, fmt.Errorf("%s must be 3 or 5, not %q", mqttProtocolVersion, version)
+	}
+}
+
+// messageProperties returns the metadata of msg, named after the fields of its payload.
+func messageProperties(msg *db.ResourceMessage) [][2]string {
+	properties := [][2]string{
+		{"consumerId", msg.ConsumerId},
+		{"resourceId", msg.Id},
+		{"resourceGenerationID", strconv.FormatInt(msg.ResourceGenerationID, 10)},
+		{"sentTimestamp", strconv.FormatInt(msg.SentTimestamp, 10)},
+	}
+	if msg.DeletionTimestamp != 0 {
+		properties = append(properties, [2]string{"deletionTimestamp", strconv.FormatInt(msg.DeletionTimestamp, 10)})
+	}
+	return properties
+}
+
+type v3Transport struct {
+	client  mqtt.Client
+	handler mqtt.MessageHandler
+}
+
+func newV3Transport(handler func(*Message)) (*v3Transport, error) {
+	t := &v3Transport{
+		handler: func(_ mqtt.Client, msg mqtt.Message) {
+			handler(&Message{Topic: msg.Topic(), Payload: msg.Payload()})
+		},
+	}
+
+	client, err := NewClient(t.handler)
+	if err != nil {
+		return nil, err
+	}
+
+	if token := client.Connect(); token.Wait() && token.Error() != nil {
+		return nil, token.Error()
+	}
+
+	t.client = client
+	return t, nil
+}
+
+func (t *v3Transport) Publish(p *publication) error {
+	token := t.client.Publish(p.topic, 1, false, p.payload)
+	token.Wait()
+	return token.Error()
+}
+
+func (t *v3Transport) Subscribe(filter string) error {
+	token := t.client.Subscribe(filter, 1, t.handler)
+	token.Wait()
+	return token.Error()
+}
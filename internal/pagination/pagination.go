@@ -0,0 +1,37 @@
+// Package pagination implements the page tokens and page sizes of the List APIs.
+package pagination
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+const (
+	DefaultPageSize = 100
+	MaxPageSize     = 1000
+)
+
+// PageSize returns the number of items to return for the requested page size.
+func PageSize(requested int32) int {
+	if requested <= 0 {
+		return DefaultPageSize
+	}
+	if requested > MaxPageSize {
+		return MaxPageSize
+	}
+	return int(requested)
+}
+
+// EncodeToken returns an opaque page token resuming the listing after the item with the given ID.
+func EncodeToken(after string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(after))
+}
+
+// DecodeToken returns the ID encoded in the page token, an empty token starts from the beginning.
+func DecodeToken(token string) (string, error) {
+	after, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return "", fmt.Errorf("invalid page token: %w", err)
+	}
+	return string(after), nil
+}
@@ -0,0 +1,168 @@
+package rpcerror
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/kube-orchestra/maestro/internal/db"
+	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/runtime/protoiface"
+	"google.golang.org/protobuf/types/known/anypb"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// Domain is the domain of the ErrorInfo details of the errors returned by maestro.
+const Domain = "maestro.kube-orchestra.io"
+
+// internalErrorMessage is the message of the unexpected errors, detailed in the server logs.
+const internalErrorMessage = "internal error, see the server logs"
+
+// Reasons of the ErrorInfo details, stable identifiers clients can switch on.
+// Errors without a more specific reason use the name of their code, e.g. "NOT_FOUND".
+const (
+	ReasonInvalidObject          = "INVALID_OBJECT"
+	ReasonUnknownKind            = "UNKNOWN_KIND"
+	ReasonGenerationMismatch     = "GENERATION_MISMATCH"
+	ReasonConcurrentModification = "CONCURRENT_MODIFICATION"
+	ReasonFieldManagerConflict   = "FIELD_MANAGER_CONFLICT"
+	ReasonResourceDeleting       = "RESOURCE_DELETING"
+	ReasonRevisionNotKept        = "REVISION_NOT_KEPT"
+	ReasonUnknownGeneration      = "UNKNOWN_GENERATION"
+	ReasonContentNotReported     = "CONTENT_NOT_REPORTED"
+	ReasonConsumerHasResources   = "CONSUMER_HAS_RESOURCES"
+	ReasonConsumerDeleting       = "CONSUMER_DELETING"
+	ReasonIdempotencyKeyReused   = "IDEMPOTENCY_KEY_REUSED"
+	ReasonIdempotencyKeyInUse    = "IDEMPOTENCY_KEY_IN_USE"
+)
+
+// Status returns a status of code with msg, detailed by an ErrorInfo of reason with metadata
+// followed by details. An empty reason defaults to the name of the code.
+func Status(c codes.Code, reason string, metadata map[string]string, msg string, details ...protoiface.MessageV1) *status.Status {
+	if reason == "" {
+		reason = code.Code_name[int32(c)]
+	}
+
+	st := status.New(c, msg)
+	info := &errdetails.ErrorInfo{Reason: reason, Domain: Domain, Metadata: metadata}
+	withDetails, err := st.WithDetails(append([]protoiface.MessageV1{info}, details...)...)
+	if err != nil {
+		return st
+	}
+	return withDetails
+}
+
+// Error is Status as an error.
+func Error(c codes.Code, reason string, metadata map[string]string, msg string, details ...protoiface.MessageV1) error {
+	return Status(c, reason, metadata, msg, details...).Err()
+}
+
+// InvalidField returns an InvalidArgument error for the request field, described in a BadRequest detail.
+func InvalidField(field, description string) error {
+	return Error(codes.InvalidArgument, "", nil, description, &errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: field, Description: description}},
+	})
+}
+
+// InvalidFields returns an InvalidArgument error for the invalid fields of the request,
+// described in a BadRequest detail.
+func InvalidFields(allErrs field.ErrorList) error {
+	badRequest := &errdetails.BadRequest{}
+	for _, e := range allErrs {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       e.Field,
+			Description: e.ErrorBody(),
+		})
+	}
+
+	return Error(codes.InvalidArgument, "", nil, allErrs.ToAggregate().Error(), badRequest)
+}
+
+// InvalidObject returns an InvalidArgument error for the invalid fields of the object of the request,
+// described in a BadRequest detail.
+func InvalidObject(msg string, allErrs field.ErrorList) error {
+	badRequest := &errdetails.BadRequest{}
+	for _, e := range allErrs {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       "object." + e.Field,
+			Description: e.ErrorBody(),
+		})
+	}
+
+	return Error(codes.InvalidArgument, ReasonInvalidObject, nil, msg+": "+allErrs.ToAggregate().Error(), badRequest)
+}
+
+// FromError returns the status of err: its own when it has one, NotFound, AlreadyExists and Aborted
+// for the store errors and Internal otherwise. The status always has an ErrorInfo detail.
+// The message of the other errors, e.g. of the database drivers, is logged rather than returned.
+func FromError(err error) *status.Status {
+	var grpcStatus interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &grpcStatus) {
+		return withErrorInfo(grpcStatus.GRPCStatus())
+	}
+
+	var notFound *db.ErrorNotFound
+	if errors.As(err, &notFound) {
+		metadata := map[string]string{}
+		if notFound.Kind != "" {
+			metadata["kind"] = notFound.Kind
+		}
+		return Status(codes.NotFound, "", metadata, notFound.Error())
+	}
+
+	var alreadyExists *db.ErrorAlreadyExists
+	if errors.As(err, &alreadyExists) {
+		return Status(codes.AlreadyExists, "", map[string]string{"kind": alreadyExists.Kind}, alreadyExists.Error())
+	}
+
+	var conflict *db.ErrorConflict
+	if errors.As(err, &conflict) {
+		return Status(codes.Aborted, ReasonConcurrentModification, nil, conflict.Error())
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return withErrorInfo(status.FromContextError(err))
+	}
+
+	log.Printf("Internal error: %v", err)
+	return Status(codes.Internal, "", nil, internalErrorMessage)
+}
+
+// withErrorInfo adds an ErrorInfo named after the code to the statuses that have none.
+func withErrorInfo(st *status.Status) *status.Status {
+	for _, detail := range st.Details() {
+		if _, ok := detail.(*errdetails.ErrorInfo); ok {
+			return st
+		}
+	}
+
+	info, err := anypb.New(&errdetails.ErrorInfo{Reason: code.Code_name[int32(st.Code())], Domain: Domain})
+	if err != nil {
+		return st
+	}
+
+	p := st.Proto()
+	p.Details = append([]*anypb.Any{info}, p.Details...)
+	return status.FromProto(p)
+}
+
+// UnaryServerInterceptor returns the errors of the handlers as statuses with an ErrorInfo detail.
+func UnaryServerInterceptor(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return nil, FromError(err).Err()
+	}
+	return resp, nil
+}
+
+// StreamServerInterceptor returns the errors of the handlers as statuses with an ErrorInfo detail.
+func StreamServerInterceptor(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := handler(srv, ss); err != nil {
+		return FromError(err).Err()
+	}
+	return nil
+}
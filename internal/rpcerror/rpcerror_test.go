@@ -0,0 +1,86 @@
+package rpcerror
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kube-orchestra/maestro/internal/db"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func errorInfoReason(t *testing.T, st *status.Status) string {
+	t.Helper()
+
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info.Reason
+		}
+	}
+	t.Fatalf("status %v has no ErrorInfo", st)
+	return ""
+}
+
+func TestFromError(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		code    codes.Code
+		reason  string
+		message string
+	}{
+		{
+			name:    "not found",
+			err:     fmt.Errorf("reading: %w", &db.ErrorNotFound{Kind: "Consumer"}),
+			code:    codes.NotFound,
+			reason:  "NOT_FOUND",
+			message: (&db.ErrorNotFound{Kind: "Consumer"}).Error(),
+		},
+		{
+			name:    "conflict",
+			err:     &db.ErrorConflict{},
+			code:    codes.Aborted,
+			reason:  ReasonConcurrentModification,
+			message: (&db.ErrorConflict{}).Error(),
+		},
+		{
+			name:    "own status",
+			err:     Error(codes.FailedPrecondition, ReasonUnknownKind, nil, "unknown kind"),
+			code:    codes.FailedPrecondition,
+			reason:  ReasonUnknownKind,
+			message: "unknown kind",
+		},
+		{
+			name:    "status without ErrorInfo",
+			err:     status.Error(codes.InvalidArgument, "invalid patch"),
+			code:    codes.InvalidArgument,
+			reason:  "INVALID_ARGUMENT",
+			message: "invalid patch",
+		},
+		{
+			name:    "unexpected",
+			err:     errors.New(`pq: relation "resources" does not exist`),
+			code:    codes.Internal,
+			reason:  "INTERNAL",
+			message: internalErrorMessage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := FromError(tt.err)
+			if st.Code() != tt.code || st.Message() != tt.message {
+				t.Errorf("got %v %q, want %v %q", st.Code(), st.Message(), tt.code, tt.message)
+			}
+			if reason := errorInfoReason(t, st); reason != tt.reason {
+				t.Errorf("got reason %s, want %s", reason, tt.reason)
+			}
+			if tt.code == codes.Internal && strings.Contains(st.Message(), "resources") {
+				t.Errorf("internal error leaked to the client: %q", st.Message())
+			}
+		})
+	}
+}
@@ -2,51 +2,204 @@ package consumers
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/kube-orchestra/maestro/internal/db"
+	"github.com/kube-orchestra/maestro/internal/pagination"
+	"github.com/kube-orchestra/maestro/internal/rpcerror"
+	"github.com/kube-orchestra/maestro/internal/watch"
 	v1 "github.com/kube-orchestra/maestro/proto/api/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/labels"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
+// ResourceDeleter starts the deletion of a resource on its consumer.
+type ResourceDeleter interface {
+	DeleteResource(res *db.Resource) error
+}
+
+// CRDCache caches the CustomResourceDefinitions of the consumers.
+type CRDCache interface {
+	InvalidateCRDs(consumerID string)
+}
+
 type Service struct {
 	v1.UnimplementedConsumerServiceServer
+	store           db.Store
+	hub             *watch.Hub
+	resourceDeleter ResourceDeleter
+	crdCache        CRDCache
 }
 
-func NewConsumerService() *Service {
-	return &Service{}
+func NewConsumerService(store db.Store, hub *watch.Hub, resourceDeleter ResourceDeleter, crdCache CRDCache) *Service {
+	return &Service{store: store, hub: hub, resourceDeleter: resourceDeleter, crdCache: crdCache}
 }
 
 func (svc *Service) Read(_ context.Context, r *v1.ConsumerReadRequest) (*v1.Consumer, error) {
-	c, err := db.GetConsumer(r.Id)
+	c, err := svc.store.GetConsumer(r.Id)
 	if err != nil {
 		return nil, err
 	}
 	return c, nil
 }
 
+func (svc *Service) List(_ context.Context, r *v1.ConsumerListRequest) (*v1.ConsumerListResponse, error) {
+	selector, err := labels.Parse(r.LabelSelector)
+	if err != nil {
+		return nil, rpcerror.InvalidField("labelSelector", fmt.Sprintf("invalid label selector: %v", err))
+	}
+
+	after, err := pagination.DecodeToken(r.PageToken)
+	if err != nil {
+		return nil, rpcerror.InvalidField("pageToken", err.Error())
+	}
+
+	// taken before listing, watching from it may replay changes already listed but cannot miss any
+	resourceVersion := svc.hub.Version()
+
+	// ask for one more consumer to know if there is a next page
+	pageSize := pagination.PageSize(r.PageSize)
+	consumers, err := svc.store.ListConsumers(db.ConsumerListOptions{
+		Selector: selector,
+		After:    after,
+		Limit:    pageSize + 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &v1.ConsumerListResponse{Consumers: consumers, ResourceVersion: resourceVersion}
+	if len(consumers) > pageSize {
+		response.Consumers = consumers[:pageSize]
+		response.NextPageToken = pagination.EncodeToken(consumers[pageSize-1].Id)
+	}
+
+	return response, nil
+}
+
+func (svc *Service) Watch(r *v1.ConsumerWatchRequest, stream v1.ConsumerService_WatchServer) error {
+	selector, err := labels.Parse(r.LabelSelector)
+	if err != nil {
+		return rpcerror.InvalidField("labelSelector", fmt.Sprintf("invalid label selector: %v", err))
+	}
+
+	sub, err := svc.hub.Subscribe(r.ResourceVersion)
+	if errors.Is(err, watch.ErrExpired) {
+		return status.Error(codes.OutOfRange, err.Error())
+	}
+	if err != nil {
+		return rpcerror.InvalidField("resourceVersion", err.Error())
+	}
+	defer sub.Stop()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case e, ok := <-sub.Events():
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "watch fell behind, resume from the last resourceVersion received")
+			}
+
+			if e.Consumer == nil {
+				continue
+			}
+
+			eventType := selectedEventType(selector, e)
+			if eventType == v1.ConsumerWatchEvent_UNKNOWN {
+				continue
+			}
+
+			err := stream.Send(&v1.ConsumerWatchEvent{
+				Type:            eventType,
+				Consumer:        e.Consumer,
+				ResourceVersion: e.Version,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// selectedEventType returns the type of the event as seen through the label selector,
+// or UNKNOWN when the consumer is not selected before nor after the change.
+func selectedEventType(selector labels.Selector, e watch.Event) v1.ConsumerWatchEvent_Type {
+	selected := selector.Matches(consumerLabels(e.Consumer))
+
+	switch e.Type {
+	case watch.Added:
+		if selected {
+			return v1.ConsumerWatchEvent_ADDED
+		}
+	case watch.Deleted:
+		if selected {
+			return v1.ConsumerWatchEvent_DELETED
+		}
+	case watch.Modified:
+		wasSelected := e.OldConsumer != nil && selector.Matches(consumerLabels(e.OldConsumer))
+		switch {
+		case wasSelected && selected:
+			return v1.ConsumerWatchEvent_MODIFIED
+		case selected:
+			return v1.ConsumerWatchEvent_ADDED
+		case wasSelected:
+			return v1.ConsumerWatchEvent_DELETED
+		}
+	}
+
+	return v1.ConsumerWatchEvent_UNKNOWN
+}
+
+func consumerLabels(c *v1.Consumer) labels.Set {
+	set := labels.Set{}
+	for _, l := range c.Labels {
+		set[l.Key] = l.Value
+	}
+	return set
+}
+
 type ConsumerExistsError struct{}
 
 func (m *ConsumerExistsError) Error() string {
 	return "Consumer already exists, use method PUT to update"
 }
 
+func (m *ConsumerExistsError) GRPCStatus() *status.Status {
+	return rpcerror.Status(codes.AlreadyExists, "", nil, m.Error())
+}
+
+// Create registers a consumer under the requested ID, or a random UUID when none is requested.
 func (svc *Service) Create(_ context.Context, r *v1.ConsumerCreateRequest) (*v1.Consumer, error) {
-	if r.Id != "" {
-		c, err := db.GetConsumer(r.Id)
-		if err != nil {
-			return nil, err
-		}
-		if c != nil {
-			return nil, &ConsumerExistsError{}
-		}
+	id := r.Id
+	if id == "" {
+		id = uuid.NewString()
+	} else if msgs := utilvalidation.IsDNS1123Subdomain(id); len(msgs) > 0 {
+		return nil, rpcerror.InvalidField("id", fmt.Sprintf("invalid consumer id %q: %s", id, strings.Join(msgs, ", ")))
+	}
+
+	if allErrs := validateLabels(r.Labels); len(allErrs) > 0 {
+		return nil, rpcerror.InvalidFields(allErrs)
 	}
 
 	newConsumer := &v1.Consumer{
-		Id:     uuid.NewString(),
+		Id:     id,
 		Labels: r.Labels,
 	}
 
-	err := db.PutConsumer(newConsumer)
+	err := svc.store.CreateConsumer(newConsumer)
+	var alreadyExists *db.ErrorAlreadyExists
+	if errors.As(err, &alreadyExists) {
+		return nil, &ConsumerExistsError{}
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -60,24 +213,160 @@ func (m *ConsumerDoesNotExistError) Error() string {
 	return "Consumer doesn't exist, use method create it with method POST first"
 }
 
+func (m *ConsumerDoesNotExistError) GRPCStatus() *status.Status {
+	return rpcerror.Status(codes.NotFound, "", map[string]string{"kind": "Consumer"}, m.Error())
+}
+
+// Update replaces the labels of the consumer, its deletion is left as is.
 func (svc *Service) Update(_ context.Context, c *v1.ConsumerUpdateRequest) (*v1.Consumer, error) {
-	consumer, err := db.GetConsumer(c.Id)
+	if allErrs := validateLabels(c.Labels); len(allErrs) > 0 {
+		return nil, rpcerror.InvalidFields(allErrs)
+	}
+
+	old, err := svc.store.UpdateConsumer(&v1.Consumer{Id: c.Id, Labels: c.Labels})
+	var notFound *db.ErrorNotFound
+	if errors.As(err, &notFound) {
+		return nil, &ConsumerDoesNotExistError{}
+	}
 	if err != nil {
 		return nil, err
 	}
-	if consumer == nil {
-		return nil, &ConsumerDoesNotExistError{}
+
+	return &v1.Consumer{
+		Id:                c.Id,
+		Labels:            c.Labels,
+		DeletionTimestamp: old.DeletionTimestamp,
+	}, nil
+}
+
+// validateLabels checks that the labels are valid Kubernetes labels with distinct keys.
+func validateLabels(labels []*v1.ConsumerLabel) field.ErrorList {
+	var allErrs field.ErrorList
+	keys := map[string]bool{}
+	for i, l := range labels {
+		path := field.NewPath("labels").Index(i)
+		for _, msg := range utilvalidation.IsQualifiedName(l.Key) {
+			allErrs = append(allErrs, field.Invalid(path.Child("key"), l.Key, msg))
+		}
+		for _, msg := range utilvalidation.IsValidLabelValue(l.Value) {
+			allErrs = append(allErrs, field.Invalid(path.Child("value"), l.Value, msg))
+		}
+		if keys[l.Key] {
+			allErrs = append(allErrs, field.Duplicate(path.Child("key"), l.Key))
+		}
+		keys[l.Key] = true
 	}
+	return allErrs
+}
+
+type ConsumerHasResourcesError struct{}
 
-	updatedConsumer := &v1.Consumer{
-		Id:     c.Id,
-		Labels: c.Labels,
+func (m *ConsumerHasResourcesError) Error() string {
+	return "Consumer still owns resources, delete them first or use the ORPHAN or CASCADE policy"
+}
+
+func (m *ConsumerHasResourcesError) GRPCStatus() *status.Status {
+	return rpcerror.Status(codes.FailedPrecondition, rpcerror.ReasonConsumerHasResources, nil, m.Error())
+}
+
+func (svc *Service) Delete(_ context.Context, r *v1.ConsumerDeleteRequest) (*v1.Consumer, error) {
+	consumer, err := svc.store.GetConsumer(r.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Policy == v1.ConsumerDeleteRequest_ORPHAN {
+		if err := svc.deleteConsumer(r.Id); err != nil {
+			return nil, err
+		}
+		return consumer, nil
+	}
+
+	// mark the consumer as being deleted before looking for its resources: a resource created
+	// meanwhile is either listed or sees the mark and is not kept
+	marked, err := svc.markDeleting(consumer)
+	if err != nil {
+		return nil, err
 	}
 
-	err = db.PutConsumer(updatedConsumer)
+	opts := db.ResourceListOptions{ConsumerId: r.Id}
+	if r.Policy != v1.ConsumerDeleteRequest_CASCADE {
+		opts.Limit = 1
+	}
+	resources, err := svc.store.ListResources(opts)
+	if err == nil && len(resources) > 0 {
+		if r.Policy == v1.ConsumerDeleteRequest_CASCADE {
+			return svc.deleteResources(consumer, resources)
+		}
+		err = &ConsumerHasResourcesError{}
+	}
+	if err == nil {
+		err = svc.deleteConsumer(r.Id)
+	}
 	if err != nil {
+		// a refused deletion leaves the consumer as it was, a cascading one is retried
+		if marked && r.Policy != v1.ConsumerDeleteRequest_CASCADE {
+			svc.unmarkDeleting(consumer)
+		}
 		return nil, err
 	}
 
-	return updatedConsumer, nil
+	return consumer, nil
+}
+
+func (svc *Service) deleteConsumer(consumerID string) error {
+	if err := svc.store.DeleteConsumer(consumerID); err != nil {
+		return err
+	}
+	svc.crdCache.InvalidateCRDs(consumerID)
+	return nil
+}
+
+// markDeleting sets the deletion timestamp of the consumer unless it is already being deleted,
+// and reports whether it did.
+func (svc *Service) markDeleting(consumer *v1.Consumer) (bool, error) {
+	if consumer.DeletionTimestamp != 0 {
+		return false, nil
+	}
+
+	deletionTimestamp := time.Now().Unix()
+	_, err := svc.store.SetConsumerDeletionTimestamp(consumer.Id, 0, deletionTimestamp)
+	var conflict *db.ErrorConflict
+	if errors.As(err, &conflict) {
+		// marked by a concurrent deletion meanwhile
+		stored, err := svc.store.GetConsumer(consumer.Id)
+		if err != nil {
+			return false, err
+		}
+		consumer.DeletionTimestamp = stored.DeletionTimestamp
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	consumer.DeletionTimestamp = deletionTimestamp
+	return true, nil
+}
+
+// unmarkDeleting clears the deletion timestamp set by markDeleting.
+func (svc *Service) unmarkDeleting(consumer *v1.Consumer) {
+	_, err := svc.store.SetConsumerDeletionTimestamp(consumer.Id, consumer.DeletionTimestamp, 0)
+	if err != nil {
+		log.Printf("Failed to clear the deletion timestamp of consumer %s: %v", consumer.Id, err)
+		return
+	}
+	consumer.DeletionTimestamp = 0
+}
+
+// deleteResources starts the deletion of the resources of the consumer, marked as being deleted.
+// The consumer is kept for the agent to report the deletion of the resources, and removed
+// with the last one of them.
+func (svc *Service) deleteResources(consumer *v1.Consumer, resources []*db.Resource) (*v1.Consumer, error) {
+	for _, res := range resources {
+		if err := svc.resourceDeleter.DeleteResource(res); err != nil {
+			return nil, err
+		}
+	}
+	return consumer, nil
 }
@@ -0,0 +1,208 @@
+package consumers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/kube-orchestra/maestro/internal/db"
+	"github.com/kube-orchestra/maestro/internal/rpcerror"
+	"github.com/kube-orchestra/maestro/internal/watch"
+	v1 "github.com/kube-orchestra/maestro/proto/api/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+func newTestService(t *testing.T) (*Service, db.Store) {
+	t.Helper()
+
+	hub := watch.NewHub()
+	store := watch.NewStore(db.NewMemoryStore(), hub)
+	return NewConsumerService(store, hub, nil, nil), store
+}
+
+func label(key, value string) *v1.ConsumerLabel {
+	return &v1.ConsumerLabel{Key: key, Value: value}
+}
+
+// onlyField reports whether there are fields, all of them field.
+func onlyField(fields []string, field string) bool {
+	for _, f := range fields {
+		if f != field {
+			return false
+		}
+	}
+	return len(fields) > 0
+}
+
+// fieldViolations returns the code of err and the fields of its BadRequest detail.
+func fieldViolations(err error) (codes.Code, []string) {
+	st := rpcerror.FromError(err)
+	var fields []string
+	for _, d := range st.Details() {
+		if badRequest, ok := d.(*errdetails.BadRequest); ok {
+			for _, v := range badRequest.FieldViolations {
+				fields = append(fields, v.Field)
+			}
+		}
+	}
+	return st.Code(), fields
+}
+
+func TestUpdateKeepsDeletion(t *testing.T) {
+	svc, store := newTestService(t)
+	if err := store.CreateConsumer(&v1.Consumer{Id: "c1", DeletionTimestamp: 42}); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := svc.Update(context.Background(), &v1.ConsumerUpdateRequest{
+		Id:     "c1",
+		Labels: []*v1.ConsumerLabel{label("env", "prod")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.DeletionTimestamp != 42 {
+		t.Errorf("got deletion timestamp %d, want the stored one", updated.DeletionTimestamp)
+	}
+
+	stored, err := store.GetConsumer("c1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.DeletionTimestamp != 42 || len(stored.Labels) != 1 || stored.Labels[0].Value != "prod" {
+		t.Errorf("got stored consumer %v, want the new labels and the deletion kept", stored)
+	}
+}
+
+func TestUpdateMissingConsumer(t *testing.T) {
+	svc, store := newTestService(t)
+
+	_, err := svc.Update(context.Background(), &v1.ConsumerUpdateRequest{Id: "c1"})
+	if code, _ := fieldViolations(err); code != codes.NotFound {
+		t.Fatalf("got %v updating a missing consumer, want NotFound", err)
+	}
+	if _, err := store.GetConsumer("c1"); err == nil {
+		t.Error("update created the missing consumer")
+	}
+}
+
+func TestInvalidLabels(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		labels []*v1.ConsumerLabel
+		field  string
+	}{
+		{name: "invalid key", labels: []*v1.ConsumerLabel{label("env", "prod"), label("-env", "prod")}, field: "labels[1].key"},
+		{name: "empty key", labels: []*v1.ConsumerLabel{label("", "prod")}, field: "labels[0].key"},
+		{name: "invalid value", labels: []*v1.ConsumerLabel{label("env", "prod/eu")}, field: "labels[0].value"},
+		{name: "duplicate key", labels: []*v1.ConsumerLabel{label("env", "prod"), label("env", "dev")}, field: "labels[1].key"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			svc, store := newTestService(t)
+
+			_, err := svc.Create(context.Background(), &v1.ConsumerCreateRequest{Id: "c1", Labels: tc.labels})
+			if code, fields := fieldViolations(err); code != codes.InvalidArgument || !onlyField(fields, tc.field) {
+				t.Errorf("got %v creating a consumer, want InvalidArgument on %s", err, tc.field)
+			}
+
+			if err := store.CreateConsumer(&v1.Consumer{Id: "c1"}); err != nil {
+				t.Fatal(err)
+			}
+			_, err = svc.Update(context.Background(), &v1.ConsumerUpdateRequest{Id: "c1", Labels: tc.labels})
+			if code, fields := fieldViolations(err); code != codes.InvalidArgument || !onlyField(fields, tc.field) {
+				t.Errorf("got %v updating a consumer, want InvalidArgument on %s", err, tc.field)
+			}
+		})
+	}
+}
+
+func TestListPages(t *testing.T) {
+	svc, store := newTestService(t)
+	for _, id := range []string{"c1", "c2", "c3", "c4", "c5"} {
+		env := "prod"
+		if id == "c4" {
+			env = "dev"
+		}
+		if err := store.CreateConsumer(&v1.Consumer{Id: id, Labels: []*v1.ConsumerLabel{label("env", env)}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var pages [][]string
+	request := &v1.ConsumerListRequest{LabelSelector: "env=prod", PageSize: 2}
+	for {
+		response, err := svc.List(context.Background(), request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, c := range response.Consumers {
+			ids = append(ids, c.Id)
+		}
+		pages = append(pages, ids)
+		if response.NextPageToken == "" {
+			break
+		}
+		request.PageToken = response.NextPageToken
+	}
+
+	if want := [][]string{{"c1", "c2"}, {"c3", "c5"}}; !reflect.DeepEqual(pages, want) {
+		t.Errorf("listed pages %v, want %v", pages, want)
+	}
+}
+
+func TestListInvalidArguments(t *testing.T) {
+	svc, _ := newTestService(t)
+
+	for _, tc := range []struct {
+		request *v1.ConsumerListRequest
+		field   string
+	}{
+		{request: &v1.ConsumerListRequest{LabelSelector: "env in prod"}, field: "labelSelector"},
+		{request: &v1.ConsumerListRequest{LabelSelector: "env=prod,-tier"}, field: "labelSelector"},
+		{request: &v1.ConsumerListRequest{PageToken: "not a token"}, field: "pageToken"},
+	} {
+		_, err := svc.List(context.Background(), tc.request)
+		if code, fields := fieldViolations(err); code != codes.InvalidArgument || !onlyField(fields, tc.field) {
+			t.Errorf("got %v listing with %v, want InvalidArgument on %s", err, tc.request, tc.field)
+		}
+	}
+}
+
+type noCRDCache struct{}
+
+func (noCRDCache) InvalidateCRDs(string) {}
+
+func TestDeleteRefusesConsumerWithResources(t *testing.T) {
+	hub := watch.NewHub()
+	store := watch.NewStore(db.NewMemoryStore(), hub)
+	svc := NewConsumerService(store, hub, nil, noCRDCache{})
+	for _, id := range []string{"c1", "c2"} {
+		if err := store.CreateConsumer(&v1.Consumer{Id: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.CreateResource(&db.Resource{Id: "r1", ConsumerId: "c1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := svc.Delete(context.Background(), &v1.ConsumerDeleteRequest{Id: "c1"})
+	if code, _ := fieldViolations(err); code != codes.FailedPrecondition {
+		t.Fatalf("got %v deleting a consumer with resources, want FailedPrecondition", err)
+	}
+	stored, err := store.GetConsumer("c1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.DeletionTimestamp != 0 {
+		t.Errorf("got deletion timestamp %d after a refused deletion, want none", stored.DeletionTimestamp)
+	}
+
+	if _, err := svc.Delete(context.Background(), &v1.ConsumerDeleteRequest{Id: "c2"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.GetConsumer("c2"); err == nil {
+		t.Error("consumer without resources kept")
+	}
+}
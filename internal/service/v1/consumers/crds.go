@@ -0,0 +1,84 @@
+package consumers
+
+import (
+	"context"
+
+	"github.com/kube-orchestra/maestro/internal/db"
+	"github.com/kube-orchestra/maestro/internal/rpcerror"
+	"github.com/kube-orchestra/maestro/internal/validation"
+	v1 "github.com/kube-orchestra/maestro/proto/api/v1"
+	"google.golang.org/protobuf/types/known/structpb"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func (svc *Service) PutCRD(_ context.Context, r *v1.ConsumerCRDPutRequest) (*v1.ConsumerCRD, error) {
+	if r.Object == nil {
+		return nil, rpcerror.InvalidField("object", "object is required")
+	}
+
+	// check that the consumer exists
+	if _, err := svc.store.GetConsumer(r.ConsumerId); err != nil {
+		return nil, err
+	}
+
+	obj := &unstructured.Unstructured{Object: r.Object.AsMap()}
+	if allErrs := validation.ValidateCRD(obj); len(allErrs) > 0 {
+		return nil, rpcerror.InvalidObject("invalid CustomResourceDefinition", allErrs)
+	}
+
+	c := &db.CRD{
+		ConsumerId: r.ConsumerId,
+		Name:       obj.GetName(),
+		Object:     *obj,
+	}
+	if err := svc.store.PutCRD(c); err != nil {
+		return nil, err
+	}
+	svc.crdCache.InvalidateCRDs(c.ConsumerId)
+
+	return crdToProto(c)
+}
+
+func (svc *Service) ListCRDs(_ context.Context, r *v1.ConsumerCRDListRequest) (*v1.ConsumerCRDListResponse, error) {
+	crds, err := svc.store.ListCRDs(r.ConsumerId)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &v1.ConsumerCRDListResponse{}
+	for _, c := range crds {
+		crd, err := crdToProto(c)
+		if err != nil {
+			return nil, err
+		}
+		response.Crds = append(response.Crds, crd)
+	}
+	return response, nil
+}
+
+func (svc *Service) DeleteCRD(_ context.Context, r *v1.ConsumerCRDDeleteRequest) (*v1.ConsumerCRD, error) {
+	c, err := svc.store.GetCRD(r.ConsumerId, r.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := svc.store.DeleteCRD(r.ConsumerId, r.Name); err != nil {
+		return nil, err
+	}
+	svc.crdCache.InvalidateCRDs(r.ConsumerId)
+
+	return crdToProto(c)
+}
+
+func crdToProto(c *db.CRD) (*v1.ConsumerCRD, error) {
+	object, err := structpb.NewStruct(c.Object.UnstructuredContent())
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.ConsumerCRD{
+		ConsumerId: c.ConsumerId,
+		Name:       c.Name,
+		Object:     object,
+	}, nil
+}
@@ -0,0 +1,82 @@
+package deadletters
+
+import (
+	"context"
+
+	"github.com/kube-orchestra/maestro/internal/db"
+	"github.com/kube-orchestra/maestro/internal/pagination"
+	"github.com/kube-orchestra/maestro/internal/rpcerror"
+	v1 "github.com/kube-orchestra/maestro/proto/api/v1"
+)
+
+type Service struct {
+	v1.UnimplementedDeadLetterServiceServer
+	store db.Store
+}
+
+func NewDeadLetterService(store db.Store) *Service {
+	return &Service{store: store}
+}
+
+func (svc *Service) Read(_ context.Context, r *v1.DeadLetterReadRequest) (*v1.DeadLetter, error) {
+	d, err := svc.store.GetDeadLetter(r.Id)
+	if err != nil {
+		return nil, err
+	}
+	return deadLetterToProto(d), nil
+}
+
+func (svc *Service) List(_ context.Context, r *v1.DeadLetterListRequest) (*v1.DeadLetterListResponse, error) {
+	after, err := pagination.DecodeToken(r.PageToken)
+	if err != nil {
+		return nil, rpcerror.InvalidField("pageToken", err.Error())
+	}
+
+	// ask for one more dead letter to know if there is a next page
+	pageSize := pagination.PageSize(r.PageSize)
+	deadLetters, err := svc.store.ListDeadLetters(db.DeadLetterListOptions{
+		ConsumerId: r.ConsumerId,
+		Reason:     r.Reason,
+		After:      after,
+		Limit:      pageSize + 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &v1.DeadLetterListResponse{}
+	if len(deadLetters) > pageSize {
+		deadLetters = deadLetters[:pageSize]
+		response.NextPageToken = pagination.EncodeToken(deadLetters[pageSize-1].Id)
+	}
+	for _, d := range deadLetters {
+		response.DeadLetters = append(response.DeadLetters, deadLetterToProto(d))
+	}
+
+	return response, nil
+}
+
+func (svc *Service) Delete(_ context.Context, r *v1.DeadLetterDeleteRequest) (*v1.DeadLetter, error) {
+	d, err := svc.store.GetDeadLetter(r.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := svc.store.DeleteDeadLetter(r.Id); err != nil {
+		return nil, err
+	}
+	return deadLetterToProto(d), nil
+}
+
+func deadLetterToProto(d *db.DeadLetter) *v1.DeadLetter {
+	return &v1.DeadLetter{
+		Id:                d.Id,
+		Topic:             d.Topic,
+		ConsumerId:        d.ConsumerId,
+		ResourceId:        d.ResourceId,
+		Payload:           d.Payload,
+		Reason:            d.Reason,
+		Error:             d.Error,
+		ReceivedTimestamp: d.ReceivedTimestamp,
+	}
+}
@@ -0,0 +1,248 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kube-orchestra/maestro/internal/db"
+	"github.com/kube-orchestra/maestro/internal/rpcerror"
+	v1 "github.com/kube-orchestra/maestro/proto/api/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/structpb"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+)
+
+// the field ownership bookkeeping is left out of the diffs
+var diffIgnoredPaths = map[string]bool{
+	"/metadata/managedFields": true,
+}
+
+// as is the metadata maestro sets on the objects, which the targets do not keep
+var observedDiffIgnoredPaths = map[string]bool{
+	"/metadata/managedFields": true,
+	"/metadata/uid":           true,
+}
+
+func (svc *ResourcesService) Diff(_ context.Context, r *v1.ResourceDiffRequest) (*v1.ResourceDiffResponse, error) {
+	res, err := svc.store.GetResource(r.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	toGenerationID := r.ToGenerationId
+	if toGenerationID == 0 {
+		toGenerationID = res.ResourceGenerationID
+	}
+	to, err := svc.generationObject(res, "toGenerationId", toGenerationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the first generation is diffed from an empty object, generation 0
+	fromGenerationID := r.FromGenerationId
+	from := map[string]interface{}{}
+	if fromGenerationID != 0 || toGenerationID > 1 {
+		if fromGenerationID == 0 {
+			fromGenerationID = toGenerationID - 1
+		}
+		from, err = svc.generationObject(res, "fromGenerationId", fromGenerationID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	changes, err := diffObjects(from, to, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.ResourceDiffResponse{
+		FromGenerationId: fromGenerationID,
+		ToGenerationId:   toGenerationID,
+		Changes:          changes,
+	}, nil
+}
+
+func (svc *ResourcesService) ObservedDiff(_ context.Context, r *v1.ResourceObservedDiffRequest) (*v1.ResourceDiffResponse, error) {
+	res, err := svc.store.GetResource(r.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Status.ContentStatus == nil {
+		return nil, rpcerror.Error(codes.FailedPrecondition, rpcerror.ReasonContentNotReported, nil,
+			"the consumer did not report the content of the resource yet")
+	}
+
+	changes, err := diffObjects(res.Object.Object, res.Status.ContentStatus, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.ResourceDiffResponse{
+		FromGenerationId: res.ResourceGenerationID,
+		ToGenerationId:   res.Status.ResourceGenerationID,
+		Changes:          changes,
+	}, nil
+}
+
+// generationObject returns the object of res at generationID, the current one or a kept revision.
+// The generation is read from the request field named field.
+func (svc *ResourcesService) generationObject(res *db.Resource, field string, generationID int64) (map[string]interface{}, error) {
+	if generationID == res.ResourceGenerationID {
+		return res.Object.Object, nil
+	}
+	if generationID < 1 || generationID > res.ResourceGenerationID {
+		msg := fmt.Sprintf("generation %d does not exist, the resource is at generation %d", generationID, res.ResourceGenerationID)
+		return nil, rpcerror.Error(codes.InvalidArgument, rpcerror.ReasonUnknownGeneration, nil, msg, &errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: field, Description: msg}},
+		})
+	}
+
+	rev, err := svc.store.GetRevision(res.Id, generationID)
+	var notFound *db.ErrorNotFound
+	if errors.As(err, &notFound) {
+		return nil, revisionNotKept(generationID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rev.Object.Object, nil
+}
+
+// diffObjects returns the changes turning from into to, ordered by path.
+// With desiredOnly, the fields only set in to are left out.
+func diffObjects(from, to map[string]interface{}, desiredOnly bool) ([]*v1.ResourceChange, error) {
+	// compare both objects with the same numeric types, whatever store or message they were read from
+	normalizedFrom, err := normalize(from)
+	if err != nil {
+		return nil, err
+	}
+	normalizedTo, err := normalize(to)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &differ{desiredOnly: desiredOnly, ignoredPaths: diffIgnoredPaths}
+	if desiredOnly {
+		d.ignoredPaths = observedDiffIgnoredPaths
+	}
+	d.diff("", normalizedFrom, normalizedTo)
+	return d.changes, d.err
+}
+
+func normalize(object map[string]interface{}) (interface{}, error) {
+	data, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+
+	var normalized interface{}
+	err = utiljson.Unmarshal(data, &normalized)
+	return normalized, err
+}
+
+type differ struct {
+	desiredOnly  bool
+	ignoredPaths map[string]bool
+	changes      []*v1.ResourceChange
+	err          error
+}
+
+func (d *differ) diff(path string, from, to interface{}) {
+	switch fromValue := from.(type) {
+	case map[string]interface{}:
+		if toValue, ok := to.(map[string]interface{}); ok {
+			d.diffMaps(path, fromValue, toValue)
+			return
+		}
+	case []interface{}:
+		if toValue, ok := to.([]interface{}); ok {
+			d.diffLists(path, fromValue, toValue)
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(from, to) {
+		d.add(v1.ResourceChange_CHANGED, path, from, to)
+	}
+}
+
+func (d *differ) diffMaps(path string, from, to map[string]interface{}) {
+	keys := make([]string, 0, len(from)+len(to))
+	for k := range from {
+		keys = append(keys, k)
+	}
+	for k := range to {
+		if _, ok := from[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		fromValue, inFrom := from[k]
+		toValue, inTo := to[k]
+		d.diffField(path+"/"+escapePointer(k), fromValue, inFrom, toValue, inTo)
+	}
+}
+
+func (d *differ) diffLists(path string, from, to []interface{}) {
+	n := len(from)
+	if len(to) > n {
+		n = len(to)
+	}
+
+	for i := 0; i < n; i++ {
+		var fromValue, toValue interface{}
+		if i < len(from) {
+			fromValue = from[i]
+		}
+		if i < len(to) {
+			toValue = to[i]
+		}
+		d.diffField(path+"/"+strconv.Itoa(i), fromValue, i < len(from), toValue, i < len(to))
+	}
+}
+
+func (d *differ) diffField(path string, from interface{}, inFrom bool, to interface{}, inTo bool) {
+	if d.ignoredPaths[path] {
+		return
+	}
+
+	switch {
+	case inFrom && inTo:
+		d.diff(path, from, to)
+	case inFrom:
+		d.add(v1.ResourceChange_REMOVED, path, from, nil)
+	case !d.desiredOnly:
+		d.add(v1.ResourceChange_ADDED, path, nil, to)
+	}
+}
+
+func (d *differ) add(operation v1.ResourceChange_Operation, path string, from, to interface{}) {
+	if d.err != nil {
+		return
+	}
+
+	change := &v1.ResourceChange{Operation: operation, Path: path}
+	if operation != v1.ResourceChange_ADDED {
+		change.From, d.err = structpb.NewValue(from)
+	}
+	if operation != v1.ResourceChange_REMOVED && d.err == nil {
+		change.To, d.err = structpb.NewValue(to)
+	}
+	d.changes = append(d.changes, change)
+}
+
+// escapePointer escapes a key for a JSON pointer, as in RFC 6901.
+func escapePointer(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
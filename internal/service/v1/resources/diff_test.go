@@ -0,0 +1,149 @@
+package resources
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/kube-orchestra/maestro/internal/rpcerror"
+	v1 "github.com/kube-orchestra/maestro/proto/api/v1"
+	"google.golang.org/grpc/codes"
+)
+
+// change is a ResourceChange with plain values, for comparisons.
+type change struct {
+	operation v1.ResourceChange_Operation
+	path      string
+	from, to  interface{}
+}
+
+func changes(resourceChanges []*v1.ResourceChange) []change {
+	var out []change
+	for _, c := range resourceChanges {
+		out = append(out, change{operation: c.Operation, path: c.Path, from: c.From.AsInterface(), to: c.To.AsInterface()})
+	}
+	return out
+}
+
+func TestDiffObjects(t *testing.T) {
+	from := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":          "web",
+			"labels":        map[string]interface{}{"app": "web", "a/b~c": "x"},
+			"managedFields": []interface{}{"m1"},
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(1),
+			"ports":    []interface{}{int64(80), int64(443)},
+			"paused":   true,
+		},
+	}
+	to := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":          "web",
+			"labels":        map[string]interface{}{"app": "web", "tier": "front"},
+			"managedFields": []interface{}{"m2"},
+		},
+		"spec": map[string]interface{}{
+			// a number read from JSON is the same as the integer of the store
+			"replicas": float64(1),
+			"ports":    []interface{}{int64(8080)},
+			"paused":   map[string]interface{}{"until": "tomorrow"},
+		},
+	}
+
+	got, err := diffObjects(from, to, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []change{
+		{v1.ResourceChange_REMOVED, "/metadata/labels/a~1b~0c", "x", nil},
+		{v1.ResourceChange_ADDED, "/metadata/labels/tier", nil, "front"},
+		{v1.ResourceChange_CHANGED, "/spec/paused", true, map[string]interface{}{"until": "tomorrow"}},
+		{v1.ResourceChange_CHANGED, "/spec/ports/0", float64(80), float64(8080)},
+		{v1.ResourceChange_REMOVED, "/spec/ports/1", float64(443), nil},
+	}
+	if diff := changes(got); !reflect.DeepEqual(diff, want) {
+		t.Errorf("got changes %v, want %v", diff, want)
+	}
+}
+
+func TestDiffObjectsDesiredOnly(t *testing.T) {
+	desired := map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "web", "uid": "desired"},
+		"data":     map[string]interface{}{"key": "desired"},
+	}
+	observed := map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "web", "uid": "observed", "resourceVersion": "12"},
+		"data":     map[string]interface{}{"key": "observed"},
+		"status":   map[string]interface{}{"ready": true},
+	}
+
+	got, err := diffObjects(desired, observed, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []change{{v1.ResourceChange_CHANGED, "/data/key", "desired", "observed"}}
+	if diff := changes(got); !reflect.DeepEqual(diff, want) {
+		t.Errorf("got changes %v, want %v", diff, want)
+	}
+}
+
+func TestDiffFirstGeneration(t *testing.T) {
+	svc, _ := newTestService(t)
+	ctx := context.Background()
+
+	request := createRequest(t, "config")
+	request.IdempotencyKey = ""
+	res, err := svc.Create(ctx, request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := svc.Diff(ctx, &v1.ResourceDiffRequest{Id: res.Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff.FromGenerationId != 0 || diff.ToGenerationId != 1 {
+		t.Errorf("got diff from generation %d to %d, want from 0 to 1", diff.FromGenerationId, diff.ToGenerationId)
+	}
+	var paths []string
+	for _, c := range diff.Changes {
+		if c.Operation != v1.ResourceChange_ADDED {
+			t.Errorf("got %v change of %s, want every field added", c.Operation, c.Path)
+		}
+		paths = append(paths, c.Path)
+	}
+	if want := []string{"/apiVersion", "/kind", "/metadata"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("got changes of %v, want %v", paths, want)
+	}
+}
+
+func TestDiffErrors(t *testing.T) {
+	svc, _ := newTestService(t)
+	ctx := context.Background()
+
+	request := createRequest(t, "config")
+	request.IdempotencyKey = ""
+	res, err := svc.Create(ctx, request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, r := range []*v1.ResourceDiffRequest{
+		{Id: res.Id, ToGenerationId: 2},
+		{Id: res.Id, FromGenerationId: 2},
+		{Id: res.Id, FromGenerationId: -1},
+	} {
+		_, err := svc.Diff(ctx, r)
+		if code, reason := errorReason(err); code != codes.InvalidArgument || reason != rpcerror.ReasonUnknownGeneration {
+			t.Errorf("got %v diffing from generation %d to %d, want %s", err, r.FromGenerationId, r.ToGenerationId,
+				rpcerror.ReasonUnknownGeneration)
+		}
+	}
+
+	_, err = svc.ObservedDiff(ctx, &v1.ResourceObservedDiffRequest{Id: res.Id})
+	if code, reason := errorReason(err); code != codes.FailedPrecondition || reason != rpcerror.ReasonContentNotReported {
+		t.Errorf("got %v diffing an unreported content, want %s", err, rpcerror.ReasonContentNotReported)
+	}
+}
@@ -0,0 +1,148 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/kube-orchestra/maestro/internal/rpcerror"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/managedfields"
+)
+
+// maxFieldManagerLength is the maximum length of a field manager name, as in Kubernetes.
+const maxFieldManagerLength = 128
+
+// the schemas of the kinds are not known, every field is owned as a whole except
+// for maps, lists included
+var typeConverter = managedfields.NewDeducedTypeConverter()
+
+// newFieldManager returns a field manager tracking the fields of objects of kind gvk.
+func newFieldManager(gvk schema.GroupVersionKind) (*managedfields.FieldManager, error) {
+	return managedfields.NewDefaultCRDFieldManager(typeConverter, unstructuredConverter{}, unstructuredDefaulter{},
+		unstructuredCreater{}, gvk, gvk.GroupVersion(), "", nil)
+}
+
+// trackUpdate records the fields changed from live to obj as owned by manager in the managed fields of obj.
+func trackUpdate(live, obj *unstructured.Unstructured, manager string) (*unstructured.Unstructured, error) {
+	gvk := obj.GroupVersionKind()
+	fieldManager, err := newFieldManager(gvk)
+	if err != nil {
+		return nil, err
+	}
+
+	if live == nil || live.GroupVersionKind() != gvk {
+		// changing the kind of the object resets the ownership of its fields
+		live = emptyObject(gvk)
+	}
+
+	updated := fieldManager.UpdateNoErrors(live, obj, manager)
+	return updated.(*unstructured.Unstructured), nil
+}
+
+// applyObject merges applied into live as a server-side apply of manager.
+func applyObject(live, applied *unstructured.Unstructured, manager string, force bool) (*unstructured.Unstructured, error) {
+	gvk := live.GroupVersionKind()
+	if applied.GroupVersionKind() != gvk {
+		return nil, status.Errorf(codes.InvalidArgument, "applied object is of kind %s, expected %s like the resource",
+			applied.GroupVersionKind(), gvk)
+	}
+
+	fieldManager, err := newFieldManager(gvk)
+	if err != nil {
+		return nil, err
+	}
+
+	merged, err := fieldManager.Apply(live, applied, manager, force)
+	if apierrors.IsConflict(err) {
+		return nil, rpcerror.Error(codes.FailedPrecondition, rpcerror.ReasonFieldManagerConflict, nil,
+			fmt.Sprintf("%v, apply again with force to take ownership of the fields", err))
+	}
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return merged.(*unstructured.Unstructured), nil
+}
+
+// fieldManagerName returns the requested field manager, defaulting to the product of the User-Agent.
+func fieldManagerName(ctx context.Context, requested string) (string, error) {
+	if requested == "" {
+		requested = userAgentProduct(ctx)
+	}
+
+	if len(requested) > maxFieldManagerLength {
+		return "", fmt.Errorf("fieldManager must have at most %d characters", maxFieldManagerLength)
+	}
+	for _, r := range requested {
+		if !unicode.IsPrint(r) {
+			return "", errors.New("fieldManager must only contain printable characters")
+		}
+	}
+	return requested, nil
+}
+
+// userAgentProduct returns the product of the User-Agent of the gateway or gRPC client, e.g. "curl" for "curl/8.0.1".
+func userAgentProduct(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, key := range []string{"grpcgateway-user-agent", "user-agent"} {
+		if values := md.Get(key); len(values) > 0 {
+			product, _, _ := strings.Cut(values[0], "/")
+			if len(product) > maxFieldManagerLength {
+				product = product[:maxFieldManagerLength]
+			}
+			return product
+		}
+	}
+	return "unknown"
+}
+
+// withoutManagedFields returns a copy of obj without its managed fields, which are meaningless to the consumers.
+func withoutManagedFields(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	content := obj.DeepCopy()
+	unstructured.RemoveNestedField(content.Object, "metadata", "managedFields")
+	return content
+}
+
+func emptyObject(gvk schema.GroupVersionKind) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	obj.SetGroupVersionKind(gvk)
+	return obj
+}
+
+// unstructuredConverter only supports the single version objects are stored in,
+// the fields owned in other versions are dropped.
+type unstructuredConverter struct{}
+
+func (unstructuredConverter) Convert(_, _, _ interface{}) error {
+	return errors.New("conversion is not supported")
+}
+
+func (unstructuredConverter) ConvertToVersion(in runtime.Object, target runtime.GroupVersioner) (runtime.Object, error) {
+	gvk := in.GetObjectKind().GroupVersionKind()
+	if targetGVK, ok := target.KindForGroupVersionKinds([]schema.GroupVersionKind{gvk}); ok && targetGVK == gvk {
+		return in, nil
+	}
+	return nil, runtime.NewNotRegisteredGVKErrForTarget("maestro", gvk, target)
+}
+
+func (unstructuredConverter) ConvertFieldLabel(_ schema.GroupVersionKind, _, _ string) (string, string, error) {
+	return "", "", errors.New("field labels are not supported")
+}
+
+type unstructuredDefaulter struct{}
+
+func (unstructuredDefaulter) Default(_ runtime.Object) {}
+
+type unstructuredCreater struct{}
+
+func (unstructuredCreater) New(gvk schema.GroupVersionKind) (runtime.Object, error) {
+	return emptyObject(gvk), nil
+}
@@ -0,0 +1,111 @@
+package resources
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func configMap(data map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "config", "namespace": "default"},
+		"data":       data,
+	}}
+}
+
+// managers returns the names of the managers of obj.
+func managers(obj *unstructured.Unstructured) []string {
+	var names []string
+	for _, entry := range obj.GetManagedFields() {
+		names = append(names, entry.Manager)
+	}
+	return names
+}
+
+func TestTrackUpdate(t *testing.T) {
+	created, err := trackUpdate(nil, configMap(map[string]interface{}{"a": "1"}), "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := managers(created); len(got) != 1 || got[0] != "alice" {
+		t.Fatalf("got managers %v of the created object, want alice", got)
+	}
+
+	updated := configMap(map[string]interface{}{"a": "1", "b": "2"})
+	updated.SetManagedFields(created.GetManagedFields())
+	updated, err = trackUpdate(created, updated, "bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := managers(updated); len(got) != 2 || got[0] != "alice" || got[1] != "bob" {
+		t.Errorf("got managers %v of the updated object, want alice and bob", got)
+	}
+}
+
+func TestApplyObject(t *testing.T) {
+	live, err := trackUpdate(nil, configMap(map[string]interface{}{"a": "1"}), "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	merged, err := applyObject(live, configMap(map[string]interface{}{"b": "2"}), "bob", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _, _ := unstructured.NestedStringMap(merged.Object, "data"); data["a"] != "1" || data["b"] != "2" {
+		t.Errorf("got data %v after applying b, want a and b", data)
+	}
+
+	_, err = applyObject(merged, configMap(map[string]interface{}{"a": "changed"}), "bob", false)
+	if st := status.Convert(err); st.Code() != codes.FailedPrecondition ||
+		!strings.Contains(st.Message(), "conflict") {
+		t.Fatalf("got %v applying a field of another manager, want a conflict", err)
+	}
+
+	forced, err := applyObject(merged, configMap(map[string]interface{}{"a": "changed"}), "bob", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _, _ := unstructured.NestedStringMap(forced.Object, "data"); data["a"] != "changed" {
+		t.Errorf("got data %v after a forced apply, want a changed", data)
+	}
+
+	secret := configMap(nil)
+	secret.SetKind("Secret")
+	if _, err := applyObject(live, secret, "bob", false); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("got %v applying an object of another kind, want InvalidArgument", err)
+	}
+}
+
+func TestFieldManagerName(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("user-agent", "kubectl/v1.28.0 (linux)"))
+	if name, err := fieldManagerName(ctx, ""); err != nil || name != "kubectl" {
+		t.Errorf("got field manager %q, error %v, want kubectl", name, err)
+	}
+
+	gateway := metadata.NewIncomingContext(context.Background(),
+		metadata.Pairs("user-agent", "grpc-go/1.58.0", "grpcgateway-user-agent", "curl/8.0.1"))
+	if name, err := fieldManagerName(gateway, ""); err != nil || name != "curl" {
+		t.Errorf("got field manager %q, error %v, want curl", name, err)
+	}
+
+	if name, err := fieldManagerName(context.Background(), ""); err != nil || name != "unknown" {
+		t.Errorf("got field manager %q, error %v, want unknown", name, err)
+	}
+	if name, err := fieldManagerName(ctx, "deployer"); err != nil || name != "deployer" {
+		t.Errorf("got field manager %q, error %v, want deployer", name, err)
+	}
+
+	for _, requested := range []string{strings.Repeat("a", maxFieldManagerLength+1), "line\nbreak"} {
+		if _, err := fieldManagerName(ctx, requested); err == nil {
+			t.Errorf("accepted field manager %q", requested)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package resources
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+	"unicode"
+
+	"github.com/kube-orchestra/maestro/internal/db"
+	"github.com/kube-orchestra/maestro/internal/rpcerror"
+	v1 "github.com/kube-orchestra/maestro/proto/api/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+// idempotencyKeyMetadata is the metadata key the gateway forwards the Idempotency-Key header as.
+const idempotencyKeyMetadata = "idempotency-key"
+
+const maxIdempotencyKeyLength = 255
+
+// idempotencyLease is how long a request being processed holds its key,
+// should the server stop before recording the response.
+const idempotencyLease = time.Minute
+
+// idempotencyCleanupInterval is the period at which the expired idempotency records are deleted.
+const idempotencyCleanupInterval = 10 * time.Minute
+
+// requestIdempotencyKey returns the idempotency key of the request, either set
+// in the request or in the metadata, empty when the request has none.
+func requestIdempotencyKey(ctx context.Context, requested string) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	key := requested
+	if values := md.Get(idempotencyKeyMetadata); len(values) > 0 {
+		if requested != "" && requested != values[0] {
+			return "", fmt.Errorf("Idempotency-Key header %q does not match idempotencyKey %q", values[0], requested)
+		}
+		key = values[0]
+	}
+
+	if len(key) > maxIdempotencyKeyLength {
+		return "", fmt.Errorf("idempotency key must have at most %d characters", maxIdempotencyKeyLength)
+	}
+	for _, r := range key {
+		if !unicode.IsPrint(r) {
+			return "", errors.New("idempotency key must only contain printable characters")
+		}
+	}
+	return key, nil
+}
+
+// idempotent runs create once per idempotency key of the consumer within the idempotency window:
+// retries of request get the response to the first one, other requests with the same key are rejected.
+func (svc *ResourcesService) idempotent(consumerID, key string, request proto.Message, create func() (*v1.Resource, error)) (*v1.Resource, error) {
+	hash, err := requestHash(request)
+	if err != nil {
+		return nil, err
+	}
+
+	// consumer IDs cannot contain slashes
+	record := &db.IdempotencyRecord{
+		IdempotencyKey:      consumerID + "/" + key,
+		RequestHash:         hash,
+		ExpirationTimestamp: time.Now().Add(idempotencyLease).Unix(),
+	}
+
+	stored, err := svc.claimIdempotencyKey(record)
+	if err != nil {
+		return nil, err
+	}
+	if stored != nil {
+		return replay(stored, hash)
+	}
+
+	// the record is only written back while it holds the lease of this request,
+	// another request takes the key over once the lease expired
+	lease := record.ExpirationTimestamp
+
+	res, err := create()
+	if err != nil {
+		// let the retries through
+		if err := svc.store.DeleteIdempotencyRecord(record); err != nil && !leaseLost(err) {
+			log.Printf("Failed to release idempotency key %s: %v", record.IdempotencyKey, err)
+		}
+		return nil, err
+	}
+
+	record.Response, err = proto.Marshal(res)
+	if err == nil {
+		record.ExpirationTimestamp = time.Now().Add(svc.idempotencyWindow).Unix()
+		err = svc.store.UpdateIdempotencyRecord(record, lease)
+	}
+	if leaseLost(err) {
+		log.Printf("Idempotency key %s expired before the response was recorded, "+
+			"a retry may have created another resource", record.IdempotencyKey)
+	} else if err != nil {
+		// the resource is created, retries will be rejected until the lease expires
+		log.Printf("Failed to record the response for idempotency key %s: %v", record.IdempotencyKey, err)
+	}
+	return res, nil
+}
+
+// leaseLost tells whether err reports that the record of an idempotency key is no longer the claim of the request.
+func leaseLost(err error) bool {
+	var (
+		notFound *db.ErrorNotFound
+		conflict *db.ErrorConflict
+	)
+	return errors.As(err, &notFound) || errors.As(err, &conflict)
+}
+
+// claimIdempotencyKey stores record, taking over an expired record of the same key,
+// unless a record of the same key has not expired yet and is returned instead.
+func (svc *ResourcesService) claimIdempotencyKey(record *db.IdempotencyRecord) (*db.IdempotencyRecord, error) {
+	err := svc.store.CreateIdempotencyRecord(record, time.Now().Unix())
+	var alreadyExists *db.ErrorAlreadyExists
+	if !errors.As(err, &alreadyExists) {
+		return nil, err
+	}
+
+	stored, err := svc.store.GetIdempotencyRecord(record.IdempotencyKey)
+	var notFound *db.ErrorNotFound
+	if errors.As(err, &notFound) {
+		// released by a request that failed meanwhile
+		return nil, idempotencyKeyInUse()
+	}
+	return stored, err
+}
+
+// replay returns the response recorded for the request of hash.
+func replay(stored *db.IdempotencyRecord, hash string) (*v1.Resource, error) {
+	if stored.RequestHash != hash {
+		return nil, rpcerror.Error(codes.InvalidArgument, rpcerror.ReasonIdempotencyKeyReused, nil,
+			"idempotency key was already used for a different request")
+	}
+	if len(stored.Response) == 0 {
+		return nil, idempotencyKeyInUse()
+	}
+
+	res := &v1.Resource{}
+	if err := proto.Unmarshal(stored.Response, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func idempotencyKeyInUse() error {
+	return rpcerror.Error(codes.Aborted, rpcerror.ReasonIdempotencyKeyInUse, nil,
+		"a request with the same idempotency key is being processed, retry later")
+}
+
+func requestHash(request proto.Message) (string, error) {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(request)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// StartIdempotencyCleanup periodically deletes the expired idempotency records.
+func (svc *ResourcesService) StartIdempotencyCleanup() {
+	go func() {
+		for range time.Tick(idempotencyCleanupInterval) {
+			if err := svc.store.DeleteExpiredIdempotencyRecords(time.Now().Unix()); err != nil {
+				log.Printf("Failed to delete the expired idempotency records: %v", err)
+			}
+		}
+	}()
+}
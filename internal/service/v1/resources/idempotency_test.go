@@ -0,0 +1,203 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kube-orchestra/maestro/internal/db"
+	"github.com/kube-orchestra/maestro/internal/rpcerror"
+	"github.com/kube-orchestra/maestro/internal/validation"
+	"github.com/kube-orchestra/maestro/internal/watch"
+	v1 "github.com/kube-orchestra/maestro/proto/api/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// newTestService returns a service storing in a memory store with consumer c1, its resource messages sent to a buffered channel.
+func newTestService(t *testing.T) (*ResourcesService, db.Store) {
+	t.Helper()
+
+	store := db.NewMemoryStore()
+	if err := store.CreateConsumer(&v1.Consumer{Id: "c1"}); err != nil {
+		t.Fatal(err)
+	}
+	validator, err := validation.NewValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := NewResourceService(store, watch.NewHub(), make(chan db.ResourceMessage, 100), 10, validator,
+		UnknownKindsReject, time.Hour)
+	return svc, store
+}
+
+func createRequest(t *testing.T, name string) *v1.ResourceCreateRequest {
+	t.Helper()
+
+	object, err := structpb.NewStruct(map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": name, "namespace": "default"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &v1.ResourceCreateRequest{ConsumerId: "c1", Object: object, IdempotencyKey: "key"}
+}
+
+// errorReason returns the code and the ErrorInfo reason of the status of err.
+func errorReason(err error) (codes.Code, string) {
+	st := rpcerror.FromError(err)
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return st.Code(), info.Reason
+		}
+	}
+	return st.Code(), ""
+}
+
+func countResources(t *testing.T, store db.Store) int {
+	t.Helper()
+
+	resources, err := store.ListResources(db.ResourceListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(resources)
+}
+
+func TestIdempotentCreate(t *testing.T) {
+	svc, store := newTestService(t)
+	ctx := context.Background()
+
+	first, err := svc.Create(ctx, createRequest(t, "config"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	retry, err := svc.Create(ctx, createRequest(t, "config"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retry.Id != first.Id || countResources(t, store) != 1 {
+		t.Errorf("retry created resource %s besides %s", retry.Id, first.Id)
+	}
+
+	_, err = svc.Create(ctx, createRequest(t, "other"))
+	if code, reason := errorReason(err); code != codes.InvalidArgument || reason != rpcerror.ReasonIdempotencyKeyReused {
+		t.Errorf("got %v reusing the key for another request, want %s", err, rpcerror.ReasonIdempotencyKeyReused)
+	}
+}
+
+func TestIdempotencyKeyInUse(t *testing.T) {
+	svc, store := newTestService(t)
+
+	// a request with the same key is being processed
+	r := createRequest(t, "config")
+	request := proto.Clone(r).(*v1.ResourceCreateRequest)
+	request.IdempotencyKey = ""
+	hash, err := requestHash(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claim := &db.IdempotencyRecord{IdempotencyKey: "c1/key", RequestHash: hash, ExpirationTimestamp: time.Now().Add(time.Minute).Unix()}
+	if err := store.CreateIdempotencyRecord(claim, time.Now().Unix()); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = svc.Create(context.Background(), r)
+	if code, reason := errorReason(err); code != codes.Aborted || reason != rpcerror.ReasonIdempotencyKeyInUse {
+		t.Errorf("got %v while the key is in use, want %s", err, rpcerror.ReasonIdempotencyKeyInUse)
+	}
+	if countResources(t, store) != 0 {
+		t.Error("resource created while the key is in use")
+	}
+}
+
+// takeOver claims the key of svc as a request arriving once the lease of the request being processed expired.
+func takeOver(t *testing.T, store db.Store) *db.IdempotencyRecord {
+	t.Helper()
+
+	afterLease := time.Now().Add(idempotencyLease + time.Second)
+	second := &db.IdempotencyRecord{
+		IdempotencyKey:      "c1/key",
+		RequestHash:         "second",
+		ExpirationTimestamp: afterLease.Add(idempotencyLease).Unix(),
+	}
+	if err := store.CreateIdempotencyRecord(second, afterLease.Unix()); err != nil {
+		t.Fatalf("failed to take over the expired key: %v", err)
+	}
+	return second
+}
+
+func TestExpiredLeaseKeepsTheKeyOfTheNextRequest(t *testing.T) {
+	svc, store := newTestService(t)
+
+	for _, failed := range []bool{true, false} {
+		var second *db.IdempotencyRecord
+		_, _ = svc.idempotent("c1", "key", &v1.ResourceCreateRequest{ConsumerId: "c1"}, func() (*v1.Resource, error) {
+			second = takeOver(t, store)
+			if failed {
+				return nil, errors.New("failed")
+			}
+			return &v1.Resource{Id: "r1"}, nil
+		})
+
+		stored, err := store.GetIdempotencyRecord("c1/key")
+		if err != nil {
+			t.Fatalf("failed %v: the key taken over is gone: %v", failed, err)
+		}
+		if stored.RequestHash != second.RequestHash || len(stored.Response) > 0 {
+			t.Errorf("failed %v: the key taken over was overwritten with %+v", failed, stored)
+		}
+
+		if err := store.DeleteIdempotencyRecord(second); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestFailedCreateReleasesTheKey(t *testing.T) {
+	svc, store := newTestService(t)
+
+	_, err := svc.idempotent("c1", "key", &v1.ResourceCreateRequest{ConsumerId: "c1"}, func() (*v1.Resource, error) {
+		return nil, errors.New("failed")
+	})
+	if err == nil {
+		t.Fatal("error of the request lost")
+	}
+
+	var notFound *db.ErrorNotFound
+	if _, err := store.GetIdempotencyRecord("c1/key"); !errors.As(err, &notFound) {
+		t.Errorf("got %v reading the key of a failed request, want it released", err)
+	}
+}
+
+func TestCreateDoesNotWaitForABusySender(t *testing.T) {
+	svc, store := newTestService(t)
+	// nobody takes the messages
+	svc.resourceChan = make(chan db.ResourceMessage)
+	svc.sendTimeout = 10 * time.Millisecond
+
+	done := make(chan error)
+	go func() {
+		_, err := svc.Create(context.Background(), createRequest(t, "config"))
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("create blocked on the sender")
+	}
+
+	record, err := store.GetIdempotencyRecord("c1/key")
+	if err != nil || len(record.Response) == 0 {
+		t.Errorf("got record %+v, error %v, want the response recorded", record, err)
+	}
+}
@@ -0,0 +1,183 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/kube-orchestra/maestro/internal/db"
+	"github.com/kube-orchestra/maestro/internal/rpcerror"
+	"github.com/kube-orchestra/maestro/internal/validation"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// UnknownKindsReject rejects the resources of kinds unknown to their consumer.
+	UnknownKindsReject = "reject"
+	// UnknownKindsWarn accepts them, with a warning in the response.
+	UnknownKindsWarn = "warn"
+)
+
+// warningMetadata is the header metadata key of the warnings, forwarded by the gateway as Grpc-Metadata-Warning.
+const warningMetadata = "warning"
+
+// crdCacheTTL is how long the CustomResourceDefinitions of a consumer are cached.
+// The changes made through other replicas of the server are seen after it.
+const crdCacheTTL = 30 * time.Second
+
+// crdCache caches the CustomResourceDefinitions of the consumers, the writes of the
+// resources of kinds that are not built in would otherwise read them all from the store.
+type crdCache struct {
+	mu      sync.Mutex
+	entries map[string]crdCacheEntry
+	// generation is incremented by every invalidation, a load started before one is not cached.
+	generation uint64
+}
+
+type crdCacheEntry struct {
+	crds    []*validation.CustomResourceDefinition
+	expires time.Time
+}
+
+func newCRDCache() *crdCache {
+	return &crdCache{entries: map[string]crdCacheEntry{}}
+}
+
+// get returns the cached CRDs of the consumer, and the generation to put them back with when they are not cached.
+func (c *crdCache) get(consumerID string) ([]*validation.CustomResourceDefinition, bool, uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[consumerID]
+	if !ok || time.Now().After(entry.expires) {
+		delete(c.entries, consumerID)
+		return nil, false, c.generation
+	}
+	return entry.crds, true, c.generation
+}
+
+// put caches the CRDs of the consumer loaded at generation, unless they were invalidated meanwhile.
+func (c *crdCache) put(consumerID string, crds []*validation.CustomResourceDefinition, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation == c.generation {
+		c.entries[consumerID] = crdCacheEntry{crds: crds, expires: time.Now().Add(crdCacheTTL)}
+	}
+}
+
+func (c *crdCache) invalidate(consumerID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, consumerID)
+	c.generation++
+}
+
+// InvalidateCRDs drops the cached CustomResourceDefinitions of the consumer, called when they change.
+func (svc *ResourcesService) InvalidateCRDs(consumerID string) {
+	svc.crds.invalidate(consumerID)
+}
+
+// crdsChanged invalidates the CustomResourceDefinitions of the consumer of res when res is one of them.
+func (svc *ResourcesService) crdsChanged(res *db.Resource, previous schema.GroupVersionKind) {
+	if res.Object.GroupVersionKind() == validation.CRDGroupVersionKind || previous == validation.CRDGroupVersionKind {
+		svc.InvalidateCRDs(res.ConsumerId)
+	}
+}
+
+// crdValidator returns the validator of the custom resources of kind gvk on the cluster of the consumer,
+// nil when none of its CustomResourceDefinitions serves gvk.
+func (svc *ResourcesService) crdValidator(consumerID string, gvk schema.GroupVersionKind) (*validation.Validator, error) {
+	crds, err := svc.consumerCRDs(consumerID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, crd := range crds {
+		if crd.Serves(gvk) {
+			return validation.NewCRDValidator(crd)
+		}
+	}
+	return nil, nil
+}
+
+// consumerCRDs returns the CustomResourceDefinitions installed on the cluster of the consumer:
+// the ones registered for it and the ones it receives as resources.
+func (svc *ResourcesService) consumerCRDs(consumerID string) ([]*validation.CustomResourceDefinition, error) {
+	crds, ok, generation := svc.crds.get(consumerID)
+	if ok {
+		return crds, nil
+	}
+
+	crds, err := svc.loadCRDs(consumerID)
+	if err != nil {
+		return nil, err
+	}
+	svc.crds.put(consumerID, crds, generation)
+	return crds, nil
+}
+
+func (svc *ResourcesService) loadCRDs(consumerID string) ([]*validation.CustomResourceDefinition, error) {
+	registered, err := svc.store.ListCRDs(consumerID)
+	if err != nil {
+		return nil, err
+	}
+	objects := make([]map[string]interface{}, 0, len(registered))
+	for _, c := range registered {
+		objects = append(objects, c.Object.Object)
+	}
+
+	resources, err := svc.store.ListResources(db.ResourceListOptions{
+		ConsumerId: consumerID,
+		APIVersion: validation.CRDGroupVersionKind.GroupVersion().String(),
+		Kind:       validation.CRDGroupVersionKind.Kind,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, res := range resources {
+		if res.DeletionTimestamp == 0 {
+			objects = append(objects, res.Object.Object)
+		}
+	}
+
+	crds := make([]*validation.CustomResourceDefinition, 0, len(objects))
+	for _, obj := range objects {
+		crd, err := validation.CRDFromUnstructured(obj)
+		if err != nil {
+			return nil, err
+		}
+		crds = append(crds, crd)
+	}
+	return crds, nil
+}
+
+// unknownKind rejects an object of kind gvk the consumer does not serve or,
+// with the warn policy, lets it through with a warning.
+func (svc *ResourcesService) unknownKind(ctx context.Context, consumerID string, gvk schema.GroupVersionKind) error {
+	msg := fmt.Sprintf("kind %s is unknown to consumer %s, register the CustomResourceDefinition serving it first",
+		gvk, consumerID)
+
+	if svc.unknownKinds == UnknownKindsWarn {
+		log.Println("Warning:", msg)
+		// not a gRPC call when the service is used directly, there is nobody to warn
+		_ = grpc.SetHeader(ctx, metadata.Pairs(warningMetadata, msg))
+		return nil
+	}
+
+	return rpcerror.Error(codes.FailedPrecondition, rpcerror.ReasonUnknownKind, map[string]string{"kind": gvk.String()}, msg,
+		&errdetails.PreconditionFailure{
+			Violations: []*errdetails.PreconditionFailure_Violation{{
+				Type:        "KIND",
+				Subject:     gvk.String(),
+				Description: fmt.Sprintf("no CustomResourceDefinition of consumer %s serves the kind", consumerID),
+			}},
+		})
+}
@@ -0,0 +1,135 @@
+package resources
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/kube-orchestra/maestro/internal/db"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// countingStore counts the reads of the CRDs of the consumers.
+type countingStore struct {
+	db.Store
+	listCRDs int
+}
+
+func (s *countingStore) ListCRDs(consumerID string) ([]*db.CRD, error) {
+	s.listCRDs++
+	return s.Store.ListCRDs(consumerID)
+}
+
+var cronTabKind = schema.GroupVersionKind{Group: "stable.example.com", Version: "v1", Kind: "CronTab"}
+
+func cronTabCRD() *db.CRD {
+	return &db.CRD{
+		ConsumerId: "c1",
+		Name:       "crontabs.stable.example.com",
+		Object: unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apiextensions.k8s.io/v1",
+			"kind":       "CustomResourceDefinition",
+			"metadata":   map[string]interface{}{"name": "crontabs.stable.example.com"},
+			"spec": map[string]interface{}{
+				"group": "stable.example.com",
+				"names": map[string]interface{}{"kind": "CronTab", "plural": "crontabs"},
+				"scope": "Namespaced",
+				"versions": []interface{}{map[string]interface{}{
+					"name":    "v1",
+					"served":  true,
+					"storage": true,
+					"schema": map[string]interface{}{
+						"openAPIV3Schema": map[string]interface{}{"type": "object"},
+					},
+				}},
+			},
+		}},
+	}
+}
+
+func TestCRDValidatorCachesTheCRDs(t *testing.T) {
+	store := &countingStore{Store: db.NewMemoryStore()}
+	svc := &ResourcesService{store: store, crds: newCRDCache()}
+
+	validator, err := svc.crdValidator("c1", cronTabKind)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if validator != nil {
+		t.Fatal("got a validator of a kind no CRD serves")
+	}
+
+	if err := store.PutCRD(cronTabCRD()); err != nil {
+		t.Fatal(err)
+	}
+	if validator, err := svc.crdValidator("c1", cronTabKind); err != nil || validator != nil {
+		t.Fatalf("got validator %v, error %v, want the cached CRDs serving no kind", validator, err)
+	}
+	if store.listCRDs != 1 {
+		t.Fatalf("CRDs read %d times, want once", store.listCRDs)
+	}
+
+	svc.InvalidateCRDs("c1")
+	for i := 0; i < 3; i++ {
+		validator, err := svc.crdValidator("c1", cronTabKind)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if validator == nil {
+			t.Fatal("registered CRD not seen after the invalidation")
+		}
+	}
+	if store.listCRDs != 2 {
+		t.Fatalf("CRDs read %d times, want twice", store.listCRDs)
+	}
+}
+
+func TestCRDCacheDropsLoadsStartedBeforeAnInvalidation(t *testing.T) {
+	cache := newCRDCache()
+
+	_, ok, generation := cache.get("c1")
+	if ok {
+		t.Fatal("got CRDs from an empty cache")
+	}
+	cache.invalidate("c1")
+	cache.put("c1", nil, generation)
+
+	if _, ok, _ := cache.get("c1"); ok {
+		t.Fatal("cached the CRDs loaded before their invalidation")
+	}
+}
+
+func TestCRDValidatorConcurrentUse(t *testing.T) {
+	store := db.NewMemoryStore()
+	if err := store.PutCRD(cronTabCRD()); err != nil {
+		t.Fatal(err)
+	}
+	svc := &ResourcesService{store: store, crds: newCRDCache()}
+
+	cronTab := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "stable.example.com/v1",
+		"kind":       "CronTab",
+		"metadata":   map[string]interface{}{"name": "backup", "namespace": "default"},
+	}}
+
+	// the validators of concurrent writes are built from the same cached CRDs
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				validator, err := svc.crdValidator("c1", cronTabKind)
+				if err != nil || validator == nil {
+					t.Errorf("got validator %v, error %v of the registered CRD", validator, err)
+					return
+				}
+				if allErrs := validator.Validate(cronTab); len(allErrs) > 0 {
+					t.Errorf("valid custom resource rejected: %v", allErrs)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
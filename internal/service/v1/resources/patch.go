@@ -0,0 +1,93 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"mime"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	v1 "github.com/kube-orchestra/maestro/proto/api/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// contentTypeMetadata is the metadata key the gateway forwards the Content-Type header as.
+const contentTypeMetadata = "grpcgateway-content-type"
+
+var patchContentTypes = map[string]v1.ResourcePatchRequest_PatchType{
+	"application/merge-patch+json":           v1.ResourcePatchRequest_MERGE,
+	"application/json-patch+json":            v1.ResourcePatchRequest_JSON,
+	"application/strategic-merge-patch+json": v1.ResourcePatchRequest_STRATEGIC,
+}
+
+// requestPatchType returns the patch type set in the request, or the one of the
+// Content-Type of the gateway request, defaulting to a JSON merge patch.
+func requestPatchType(ctx context.Context, requested v1.ResourcePatchRequest_PatchType) (v1.ResourcePatchRequest_PatchType, error) {
+	if requested != v1.ResourcePatchRequest_UNSPECIFIED {
+		return requested, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(contentTypeMetadata)
+	if len(values) == 0 {
+		return v1.ResourcePatchRequest_MERGE, nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(values[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid Content-Type header %q: %v", values[0], err)
+	}
+	if patchType, ok := patchContentTypes[mediaType]; ok {
+		return patchType, nil
+	}
+	return v1.ResourcePatchRequest_MERGE, nil
+}
+
+// applyPatch returns the content of obj with the patch applied.
+func applyPatch(obj *unstructured.Unstructured, patchType v1.ResourcePatchRequest_PatchType, patch []byte) (map[string]interface{}, error) {
+	original, err := obj.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	var patched []byte
+	switch patchType {
+	case v1.ResourcePatchRequest_MERGE:
+		patched, err = jsonpatch.MergePatch(original, patch)
+	case v1.ResourcePatchRequest_JSON:
+		var operations jsonpatch.Patch
+		operations, err = jsonpatch.DecodePatch(patch)
+		if err == nil {
+			patched, err = operations.Apply(original)
+		}
+	case v1.ResourcePatchRequest_STRATEGIC:
+		// strategic merge patches rely on the patch strategies of the typed object
+		var dataStruct runtime.Object
+		dataStruct, err = scheme.Scheme.New(obj.GroupVersionKind())
+		if runtime.IsNotRegisteredError(err) {
+			return nil, status.Errorf(codes.InvalidArgument,
+				"strategic merge patch is not supported for %s, use a JSON merge patch instead", obj.GroupVersionKind())
+		}
+		if err == nil {
+			patched, err = strategicpatch.StrategicMergePatch(original, patch, dataStruct)
+		}
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported patch type %v", patchType)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to apply the %v patch: %v", patchType, err)
+	}
+
+	object := map[string]interface{}{}
+	// keeps integers as int64 like the stored objects
+	if err := utiljson.Unmarshal(patched, &object); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "patched object is not a JSON object: %v", err)
+	}
+	return object, nil
+}
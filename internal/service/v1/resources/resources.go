@@ -3,12 +3,28 @@ package resources
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/kube-orchestra/maestro/internal/db"
+	"github.com/kube-orchestra/maestro/internal/pagination"
+	"github.com/kube-orchestra/maestro/internal/rpcerror"
+	"github.com/kube-orchestra/maestro/internal/validation"
+	"github.com/kube-orchestra/maestro/internal/watch"
 	v1 "github.com/kube-orchestra/maestro/proto/api/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/structpb"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -19,19 +35,65 @@ func prettyPrint(i interface{}) string {
 
 type ResourcesService struct {
 	v1.UnimplementedResourceServiceServer
+	store        db.Store
+	hub          *watch.Hub
 	resourceChan chan<- db.ResourceMessage
+	// number of revisions kept per resource
+	revisionHistoryLimit int
+	validator            *validation.Validator
+	// what happens to the resources of kinds unknown to their consumer
+	unknownKinds string
+	// how long the responses to requests with an idempotency key are kept
+	idempotencyWindow time.Duration
+	crds              *crdCache
+	// how long a resource message waits for the sender
+	sendTimeout time.Duration
 }
 
-func NewResourceService(resourceChan chan<- db.ResourceMessage) *ResourcesService {
-	return &ResourcesService{resourceChan: resourceChan}
+// sendTimeout bounds the wait for the sender to take a resource message, so that
+// the requests holding an idempotency key complete well within their lease.
+const sendTimeout = 20 * time.Second
+
+func NewResourceService(store db.Store, hub *watch.Hub, resourceChan chan<- db.ResourceMessage, revisionHistoryLimit int,
+	validator *validation.Validator, unknownKinds string, idempotencyWindow time.Duration) *ResourcesService {
+	return &ResourcesService{
+		store:                store,
+		hub:                  hub,
+		resourceChan:         resourceChan,
+		revisionHistoryLimit: revisionHistoryLimit,
+		validator:            validator,
+		unknownKinds:         unknownKinds,
+		idempotencyWindow:    idempotencyWindow,
+		crds:                 newCRDCache(),
+		sendTimeout:          sendTimeout,
+	}
+}
+
+// send hands msg to the sender, unless it is not taken within the send timeout or ctx is done first:
+// the message is then dropped, like the ones the sender fails to publish.
+func (svc *ResourcesService) send(ctx context.Context, msg db.ResourceMessage) {
+	timer := time.NewTimer(svc.sendTimeout)
+	defer timer.Stop()
+
+	select {
+	case svc.resourceChan <- msg:
+	case <-timer.C:
+		log.Printf("Failed to send resource %s of consumer %s: the sender is busy", msg.Id, msg.ConsumerId)
+	case <-ctx.Done():
+		log.Printf("Failed to send resource %s of consumer %s: %v", msg.Id, msg.ConsumerId, ctx.Err())
+	}
 }
 
 func (svc *ResourcesService) Read(_ context.Context, r *v1.ResourceReadRequest) (*v1.Resource, error) {
-	res, err := db.GetResource(r.Id)
+	res, err := svc.store.GetResource(r.Id)
 	if err != nil {
 		return nil, err
 	}
 
+	return toProto(res)
+}
+
+func toProto(res *db.Resource) (*v1.Resource, error) {
 	// object to proto struct
 	objProtoStruct, err := structpb.NewStruct(res.Object.UnstructuredContent())
 	if err != nil {
@@ -51,18 +113,175 @@ func (svc *ResourcesService) Read(_ context.Context, r *v1.ResourceReadRequest)
 	}
 
 	resResponse := &v1.Resource{
-		Id:           res.Id,
-		ConsumerId:   res.ConsumerId,
-		GenerationId: res.ResourceGenerationID,
-		Object:       objProtoStruct,
-		Status:       statusProtoStruct,
+		Id:                   res.Id,
+		ConsumerId:           res.ConsumerId,
+		GenerationId:         res.ResourceGenerationID,
+		Object:               objProtoStruct,
+		Status:               statusProtoStruct,
+		DeletionTimestamp:    res.DeletionTimestamp,
+		ObservedGenerationId: res.ObservedGenerationID,
 	}
 
 	return resResponse, nil
 }
 
-func (svc *ResourcesService) Create(_ context.Context, r *v1.ResourceCreateRequest) (*v1.Resource, error) {
-	unstructuredObject := unstructured.Unstructured{Object: r.Object.AsMap()}
+func (svc *ResourcesService) List(_ context.Context, r *v1.ResourceListRequest) (*v1.ResourceListResponse, error) {
+	opts := db.ResourceListOptions{
+		ConsumerId: r.ConsumerId,
+		APIVersion: r.ApiVersion,
+		Kind:       r.Kind,
+		Namespace:  r.Namespace,
+		Name:       r.Name,
+	}
+
+	if r.Condition != "" {
+		condition, err := parseCondition(r.Condition)
+		if err != nil {
+			return nil, rpcerror.InvalidField("condition", err.Error())
+		}
+		opts.Condition = condition
+	}
+
+	after, err := pagination.DecodeToken(r.PageToken)
+	if err != nil {
+		return nil, rpcerror.InvalidField("pageToken", err.Error())
+	}
+	opts.After = after
+
+	// ask for one more resource to know if there is a next page
+	pageSize := pagination.PageSize(r.PageSize)
+	opts.Limit = pageSize + 1
+
+	// taken before listing, watching from it may replay changes already listed but cannot miss any
+	response := &v1.ResourceListResponse{ResourceVersion: svc.hub.Version()}
+
+	resources, err := svc.store.ListResources(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resources) > pageSize {
+		resources = resources[:pageSize]
+		response.NextPageToken = pagination.EncodeToken(resources[pageSize-1].Id)
+	}
+
+	for _, res := range resources {
+		resResponse, err := toProto(res)
+		if err != nil {
+			return nil, err
+		}
+		response.Resources = append(response.Resources, resResponse)
+	}
+
+	return response, nil
+}
+
+var watchEventTypes = map[watch.EventType]v1.ResourceWatchEvent_Type{
+	watch.Added:    v1.ResourceWatchEvent_ADDED,
+	watch.Modified: v1.ResourceWatchEvent_MODIFIED,
+	watch.Deleted:  v1.ResourceWatchEvent_DELETED,
+}
+
+func (svc *ResourcesService) Watch(r *v1.ResourceWatchRequest, stream v1.ResourceService_WatchServer) error {
+	sub, err := svc.hub.Subscribe(r.ResourceVersion)
+	if errors.Is(err, watch.ErrExpired) {
+		return status.Error(codes.OutOfRange, err.Error())
+	}
+	if err != nil {
+		return rpcerror.InvalidField("resourceVersion", err.Error())
+	}
+	defer sub.Stop()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case e, ok := <-sub.Events():
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "watch fell behind, resume from the last resourceVersion received")
+			}
+
+			if e.Resource == nil || (r.ConsumerId != "" && e.Resource.ConsumerId != r.ConsumerId) {
+				continue
+			}
+
+			res, err := toProto(e.Resource)
+			if err != nil {
+				return err
+			}
+
+			err = stream.Send(&v1.ResourceWatchEvent{
+				Type:            watchEventTypes[e.Type],
+				Resource:        res,
+				ResourceVersion: e.Version,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// parseCondition parses a "<type>=<status>" condition filter.
+func parseCondition(s string) (*metav1.Condition, error) {
+	conditionType, conditionStatus, found := strings.Cut(s, "=")
+	if !found || conditionType == "" {
+		return nil, fmt.Errorf("invalid condition %q, expected <type>=<status>", s)
+	}
+
+	switch metav1.ConditionStatus(conditionStatus) {
+	case metav1.ConditionTrue, metav1.ConditionFalse, metav1.ConditionUnknown:
+	default:
+		return nil, fmt.Errorf("invalid condition status %q, expected True, False or Unknown", conditionStatus)
+	}
+
+	return &metav1.Condition{
+		Type:   conditionType,
+		Status: metav1.ConditionStatus(conditionStatus),
+	}, nil
+}
+
+func (svc *ResourcesService) Create(ctx context.Context, r *v1.ResourceCreateRequest) (*v1.Resource, error) {
+	key, err := requestIdempotencyKey(ctx, r.IdempotencyKey)
+	if err != nil {
+		return nil, rpcerror.InvalidField("idempotencyKey", err.Error())
+	}
+
+	// dry runs change nothing, they can be retried as is
+	if key == "" || len(r.DryRun) > 0 {
+		return svc.create(ctx, r)
+	}
+
+	request := proto.Clone(r).(*v1.ResourceCreateRequest)
+	request.IdempotencyKey = ""
+	return svc.idempotent(r.ConsumerId, key, request, func() (*v1.Resource, error) {
+		return svc.create(ctx, r)
+	})
+}
+
+func (svc *ResourcesService) create(ctx context.Context, r *v1.ResourceCreateRequest) (*v1.Resource, error) {
+	manager, err := fieldManagerName(ctx, r.FieldManager)
+	if err != nil {
+		return nil, rpcerror.InvalidField("fieldManager", err.Error())
+	}
+
+	dryRun, err := isDryRun(r.DryRun)
+	if err != nil {
+		return nil, rpcerror.InvalidField("dryRun", err.Error())
+	}
+
+	if err := svc.checkConsumer(r.ConsumerId); err != nil {
+		return nil, err
+	}
+
+	unstructuredObject, err := trackUpdate(nil, &unstructured.Unstructured{Object: r.Object.AsMap()}, manager)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := svc.validateObject(ctx, r.ConsumerId, unstructuredObject); err != nil {
+		return nil, err
+	}
 
 	// set uid
 	uid := uuid.NewString()
@@ -71,15 +290,28 @@ func (svc *ResourcesService) Create(_ context.Context, r *v1.ResourceCreateReque
 	res := db.Resource{
 		Id:                   uid,
 		ConsumerId:           r.ConsumerId,
-		Object:               unstructuredObject,
+		Object:               *unstructuredObject,
 		ResourceGenerationID: 1,
 	}
 
-	// TODO: check that it doesn't exist
-	err := db.PutResource(&res)
+	if dryRun {
+		return toProto(&res)
+	}
+
+	err = svc.store.CreateResource(&res)
 	if err != nil {
 		return nil, err
 	}
+	// a deletion of the consumer started meanwhile marks it before looking for its resources,
+	// either it lists this one or the resource is not kept
+	if err := svc.checkConsumer(r.ConsumerId); err != nil {
+		if err := svc.store.DeleteResource(res.Id); err != nil {
+			log.Printf("Failed to remove resource %s of consumer %s being deleted: %v", res.Id, res.ConsumerId, err)
+		}
+		return nil, err
+	}
+	svc.crdsChanged(&res, schema.GroupVersionKind{})
+	svc.recordRevision(&res, manager)
 
 	messageMeta := db.MessageMeta{
 		SentTimestamp:        0,
@@ -89,49 +321,355 @@ func (svc *ResourcesService) Create(_ context.Context, r *v1.ResourceCreateReque
 		Id:          res.Id,
 		ConsumerId:  res.ConsumerId,
 		MessageMeta: messageMeta,
-		Content:     &unstructuredObject,
+		Content:     withoutManagedFields(unstructuredObject),
+	}
+	svc.send(ctx, resourceMessage)
+
+	return toProto(&res)
+}
+
+func (svc *ResourcesService) Update(ctx context.Context, r *v1.ResourceUpdateRequest) (*v1.Resource, error) {
+	manager, err := fieldManagerName(ctx, r.FieldManager)
+	if err != nil {
+		return nil, rpcerror.InvalidField("fieldManager", err.Error())
+	}
+
+	dryRun, err := isDryRun(r.DryRun)
+	if err != nil {
+		return nil, rpcerror.InvalidField("dryRun", err.Error())
+	}
+
+	res, err := svc.getForUpdate(ctx, r.Id, r.ExpectedGenerationId)
+	if err != nil {
+		return nil, err
+	}
+
+	object, err := trackUpdate(&res.Object, &unstructured.Unstructured{Object: r.Object.AsMap()}, manager)
+	if err != nil {
+		return nil, err
+	}
+
+	err = svc.replaceObject(ctx, res, object, manager, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	return toProto(res)
+}
+
+func (svc *ResourcesService) Patch(ctx context.Context, r *v1.ResourcePatchRequest) (*v1.Resource, error) {
+	patchType, err := requestPatchType(ctx, r.PatchType)
+	if err != nil {
+		return nil, rpcerror.InvalidField("patchType", err.Error())
+	}
+
+	manager, err := fieldManagerName(ctx, r.FieldManager)
+	if err != nil {
+		return nil, rpcerror.InvalidField("fieldManager", err.Error())
+	}
+
+	dryRun, err := isDryRun(r.DryRun)
+	if err != nil {
+		return nil, rpcerror.InvalidField("dryRun", err.Error())
+	}
+
+	if r.Patch == nil {
+		return nil, rpcerror.InvalidField("patch", "patch is required")
+	}
+	patch, err := r.Patch.MarshalJSON()
+	if err != nil {
+		return nil, rpcerror.InvalidField("patch", err.Error())
+	}
+
+	res, err := svc.getForUpdate(ctx, r.Id, r.ExpectedGenerationId)
+	if err != nil {
+		return nil, err
+	}
+
+	patched, err := applyPatch(&res.Object, patchType, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	object, err := trackUpdate(&res.Object, &unstructured.Unstructured{Object: patched}, manager)
+	if err != nil {
+		return nil, err
 	}
-	svc.resourceChan <- resourceMessage
 
-	return &v1.Resource{Id: res.Id,
-		ConsumerId:   res.ConsumerId,
-		GenerationId: res.ResourceGenerationID,
-		Object:       r.Object}, nil
+	err = svc.replaceObject(ctx, res, object, manager, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	return toProto(res)
 }
 
-func (svc *ResourcesService) Update(_ context.Context, r *v1.ResourceUpdateRequest) (*v1.Resource, error) {
-	// TODO: rewrite using UpdateItem dynamodb
+func (svc *ResourcesService) Apply(ctx context.Context, r *v1.ResourceApplyRequest) (*v1.Resource, error) {
+	if r.FieldManager == "" {
+		return nil, rpcerror.InvalidField("fieldManager", "fieldManager is required to apply")
+	}
+	manager, err := fieldManagerName(ctx, r.FieldManager)
+	if err != nil {
+		return nil, rpcerror.InvalidField("fieldManager", err.Error())
+	}
+
+	dryRun, err := isDryRun(r.DryRun)
+	if err != nil {
+		return nil, rpcerror.InvalidField("dryRun", err.Error())
+	}
+
+	res, err := svc.getForUpdate(ctx, r.Id, r.ExpectedGenerationId)
+	if err != nil {
+		return nil, err
+	}
+
+	object, err := applyObject(&res.Object, &unstructured.Unstructured{Object: r.Object.AsMap()}, manager, r.Force)
+	if err != nil {
+		return nil, err
+	}
+
+	err = svc.replaceObject(ctx, res, object, manager, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	return toProto(res)
+}
+
+// getForUpdate reads the resource to update, checking that it is not being
+// deleted and that it is at the generation the request is conditioned on.
+func (svc *ResourcesService) getForUpdate(ctx context.Context, id string, requestedGenerationID int64) (*db.Resource, error) {
+	expectedGenerationID, err := expectedGeneration(ctx, requestedGenerationID)
+	if err != nil {
+		return nil, rpcerror.InvalidField("expectedGenerationId", err.Error())
+	}
 
 	// check that it exists
-	res, err := db.GetResource(r.Id)
+	res, err := svc.store.GetResource(id)
 	if err != nil {
 		return nil, err
 	}
+	if res.DeletionTimestamp != 0 {
+		return nil, &ResourceDeletingError{}
+	}
+	if expectedGenerationID != 0 && res.ResourceGenerationID != expectedGenerationID {
+		return nil, generationConflict(res.ResourceGenerationID, expectedGenerationID)
+	}
+
+	return res, nil
+}
+
+// replaceObject persists object as the next generation of res written by manager and publishes it to the consumer.
+// With dryRun, res is only updated in memory.
+func (svc *ResourcesService) replaceObject(ctx context.Context, res *db.Resource, object *unstructured.Unstructured, manager string, dryRun bool) error {
+	if err := svc.validateObject(ctx, res.ConsumerId, object); err != nil {
+		return err
+	}
 
-	res.Object = unstructured.Unstructured{Object: r.Object.AsMap()}
-	res.Object.SetUID(types.UID(r.Id))
+	currentGenerationID := res.ResourceGenerationID
+	previous := res.Object.GroupVersionKind()
+	res.Object = *object
+	res.Object.SetUID(types.UID(res.Id))
 	res.ResourceGenerationID++
 
-	err = db.PutResource(res)
+	if dryRun {
+		return nil
+	}
+
+	err := svc.store.UpdateResource(res, currentGenerationID)
+	var conflict *db.ErrorConflict
+	if errors.As(err, &conflict) {
+		return rpcerror.Error(codes.Aborted, rpcerror.ReasonConcurrentModification, nil,
+			"resource was modified concurrently, read it again and retry")
+	}
 	if err != nil {
-		return nil, err
+		return err
 	}
+	svc.crdsChanged(res, previous)
+	svc.recordRevision(res, manager)
 
 	messageMeta := db.MessageMeta{
 		SentTimestamp:        0,
-		ResourceGenerationID: res.ResourceGenerationID + 1,
+		ResourceGenerationID: res.ResourceGenerationID,
 	}
 
 	resourceMessage := db.ResourceMessage{
 		Id:          res.Id,
 		ConsumerId:  res.ConsumerId,
 		MessageMeta: messageMeta,
-		Content:     &res.Object,
+		Content:     withoutManagedFields(&res.Object),
+	}
+	svc.send(ctx, resourceMessage)
+
+	return nil
+}
+
+// validateObject rejects the objects that would fail on the consumer, with the invalid fields as BadRequest details.
+// Custom resources are validated against the CustomResourceDefinitions of the consumer.
+func (svc *ResourcesService) validateObject(ctx context.Context, consumerID string, object *unstructured.Unstructured) error {
+	content := withoutManagedFields(object)
+	allErrs := svc.validator.Validate(content)
+	if len(allErrs) == 0 && !svc.validator.Knows(content.GroupVersionKind()) {
+		crdValidator, err := svc.crdValidator(consumerID, content.GroupVersionKind())
+		if err != nil {
+			return err
+		}
+		if crdValidator == nil {
+			return svc.unknownKind(ctx, consumerID, content.GroupVersionKind())
+		}
+		allErrs = crdValidator.Validate(content)
+	}
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return rpcerror.InvalidObject("invalid object", allErrs)
+}
+
+// ifMatchMetadata is the metadata key the gateway forwards the If-Match header as.
+const ifMatchMetadata = "grpcgateway-if-match"
+
+// expectedGeneration returns the generation the request is conditioned on, either
+// set in the request or as an If-Match ETag, zero when the request is unconditional.
+func expectedGeneration(ctx context.Context, requested int64) (int64, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(ifMatchMetadata)
+	if len(values) == 0 {
+		return requested, nil
+	}
+
+	etag := strings.TrimPrefix(strings.TrimSpace(values[0]), "W/")
+	if etag == "*" {
+		return requested, nil
+	}
+
+	generationID, err := strconv.ParseInt(strings.Trim(etag, `"`), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid If-Match header %q, expected the ETag of the resource", values[0])
+	}
+	if requested != 0 && requested != generationID {
+		return 0, fmt.Errorf("If-Match header %q does not match expectedGenerationId %d", values[0], requested)
+	}
+	return generationID, nil
+}
+
+func generationConflict(currentGenerationID, expectedGenerationID int64) error {
+	metadata := map[string]string{
+		"currentGenerationId":  strconv.FormatInt(currentGenerationID, 10),
+		"expectedGenerationId": strconv.FormatInt(expectedGenerationID, 10),
+	}
+	return rpcerror.Error(codes.Aborted, rpcerror.ReasonGenerationMismatch, metadata,
+		fmt.Sprintf("resource is at generation %d, not at the expected generation %d", currentGenerationID, expectedGenerationID))
+}
+
+// dryRunAll is the only dry run mode, as in Kubernetes.
+const dryRunAll = "All"
+
+// isDryRun tells whether the dryRun values of a request ask for a dry run.
+func isDryRun(values []string) (bool, error) {
+	for _, v := range values {
+		if v != dryRunAll {
+			return false, fmt.Errorf("invalid dryRun value %q, the only supported value is %s", v, dryRunAll)
+		}
+	}
+	return len(values) > 0, nil
+}
+
+type ResourceDeletingError struct{}
+
+func (m *ResourceDeletingError) Error() string {
+	return "Resource is being deleted"
+}
+
+func (m *ResourceDeletingError) GRPCStatus() *status.Status {
+	return rpcerror.Status(codes.FailedPrecondition, rpcerror.ReasonResourceDeleting, nil, m.Error())
+}
+
+// checkConsumer returns an error unless the consumer exists and is not being deleted.
+func (svc *ResourcesService) checkConsumer(consumerID string) error {
+	consumer, err := svc.store.GetConsumer(consumerID)
+	if err != nil {
+		return err
+	}
+	if consumer.DeletionTimestamp != 0 {
+		return &ConsumerDeletingError{}
+	}
+	return nil
+}
+
+type ConsumerDeletingError struct{}
+
+func (m *ConsumerDeletingError) Error() string {
+	return "Consumer is being deleted"
+}
+
+func (m *ConsumerDeletingError) GRPCStatus() *status.Status {
+	return rpcerror.Status(codes.FailedPrecondition, rpcerror.ReasonConsumerDeleting, nil, m.Error())
+}
+
+// Delete marks the resource as being deleted and asks the consumer to remove it.
+// The resource is removed from the store once the consumer reports the Deleted condition.
+func (svc *ResourcesService) Delete(_ context.Context, r *v1.ResourceDeleteRequest) (*v1.Resource, error) {
+	dryRun, err := isDryRun(r.DryRun)
+	if err != nil {
+		return nil, rpcerror.InvalidField("dryRun", err.Error())
+	}
+
+	res, err := svc.store.GetResource(r.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := svc.deleteResource(res, dryRun); err != nil {
+		return nil, err
+	}
+
+	return toProto(res)
+}
+
+// DeleteResource marks res as being deleted, persists it and publishes the deletion to its consumer.
+// Resources that are already being deleted are left untouched.
+func (svc *ResourcesService) DeleteResource(res *db.Resource) error {
+	return svc.deleteResource(res, false)
+}
+
+func (svc *ResourcesService) deleteResource(res *db.Resource, dryRun bool) error {
+	if res.DeletionTimestamp != 0 {
+		return nil
+	}
+
+	currentGenerationID := res.ResourceGenerationID
+	res.DeletionTimestamp = time.Now().Unix()
+	res.ResourceGenerationID++
+
+	if dryRun {
+		return nil
+	}
+
+	err := svc.store.UpdateResource(res, currentGenerationID)
+	var conflict *db.ErrorConflict
+	if errors.As(err, &conflict) {
+		return rpcerror.Error(codes.Aborted, rpcerror.ReasonConcurrentModification, nil,
+			"resource was modified concurrently, retry the deletion")
+	}
+	if err != nil {
+		return err
+	}
+	svc.crdsChanged(res, schema.GroupVersionKind{})
+
+	messageMeta := db.MessageMeta{
+		SentTimestamp:        0,
+		ResourceGenerationID: res.ResourceGenerationID,
+	}
+	resourceMessage := db.ResourceMessage{
+		Id:                res.Id,
+		ConsumerId:        res.ConsumerId,
+		MessageMeta:       messageMeta,
+		Content:           withoutManagedFields(&res.Object),
+		DeletionTimestamp: res.DeletionTimestamp,
 	}
-	svc.resourceChan <- resourceMessage
+	// not bound to a request, it may delete the resources of a consumer
+	svc.send(context.Background(), resourceMessage)
 
-	return &v1.Resource{Id: res.Id,
-		ConsumerId:   res.ConsumerId,
-		GenerationId: res.ResourceGenerationID,
-		Object:       r.Object}, nil
+	return nil
 }
@@ -0,0 +1,37 @@
+package resources
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kube-orchestra/maestro/internal/db"
+)
+
+// deletingStore starts the deletion of the consumer of every resource right before creating it.
+type deletingStore struct {
+	db.Store
+}
+
+func (s deletingStore) CreateResource(r *db.Resource) error {
+	if _, err := s.SetConsumerDeletionTimestamp(r.ConsumerId, 0, 42); err != nil {
+		return err
+	}
+	return s.Store.CreateResource(r)
+}
+
+func TestCreateForConsumerBeingDeleted(t *testing.T) {
+	svc, store := newTestService(t)
+	svc.store = deletingStore{store}
+
+	_, err := svc.Create(context.Background(), createRequest(t, "config"))
+	if _, ok := err.(*ConsumerDeletingError); !ok {
+		t.Fatalf("got %v creating a resource of a consumer being deleted meanwhile, want ConsumerDeletingError", err)
+	}
+	resources, err := store.ListResources(db.ResourceListOptions{ConsumerId: "c1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resources) != 0 {
+		t.Errorf("kept %d resources of a consumer being deleted", len(resources))
+	}
+}
@@ -0,0 +1,108 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/kube-orchestra/maestro/internal/db"
+	"github.com/kube-orchestra/maestro/internal/rpcerror"
+	v1 "github.com/kube-orchestra/maestro/proto/api/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func (svc *ResourcesService) ListRevisions(_ context.Context, r *v1.ResourceRevisionListRequest) (*v1.ResourceRevisionListResponse, error) {
+	// check that it exists
+	if _, err := svc.store.GetResource(r.Id); err != nil {
+		return nil, err
+	}
+
+	revisions, err := svc.store.ListRevisions(r.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &v1.ResourceRevisionListResponse{}
+	for _, rev := range revisions {
+		object, err := structpb.NewStruct(rev.Object.UnstructuredContent())
+		if err != nil {
+			return nil, err
+		}
+		response.Revisions = append(response.Revisions, &v1.ResourceRevision{
+			GenerationId:      rev.ResourceGenerationID,
+			Object:            object,
+			Author:            rev.Author,
+			CreationTimestamp: rev.CreationTimestamp,
+		})
+	}
+
+	return response, nil
+}
+
+// Rollback writes the object of a past revision as a new generation, including the field ownership it had.
+func (svc *ResourcesService) Rollback(ctx context.Context, r *v1.ResourceRollbackRequest) (*v1.Resource, error) {
+	manager, err := fieldManagerName(ctx, r.FieldManager)
+	if err != nil {
+		return nil, rpcerror.InvalidField("fieldManager", err.Error())
+	}
+
+	res, err := svc.getForUpdate(ctx, r.Id, r.ExpectedGenerationId)
+	if err != nil {
+		return nil, err
+	}
+
+	rev, err := svc.store.GetRevision(r.Id, r.GenerationId)
+	var notFound *db.ErrorNotFound
+	if errors.As(err, &notFound) {
+		return nil, revisionNotKept(r.GenerationId)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	object, err := trackUpdate(&res.Object, &rev.Object, manager)
+	if err != nil {
+		return nil, err
+	}
+
+	err = svc.replaceObject(ctx, res, object, manager, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return toProto(res)
+}
+
+// recordRevision adds the current generation of res to its history and forgets the revisions past
+// the history limit. The history is best effort, failures are logged rather than failing the write
+// that already happened.
+func (svc *ResourcesService) recordRevision(res *db.Resource, author string) {
+	err := svc.store.PutRevision(&db.Revision{
+		ResourceId:           res.Id,
+		ResourceGenerationID: res.ResourceGenerationID,
+		Object:               res.Object,
+		Author:               author,
+		CreationTimestamp:    time.Now().Unix(),
+	})
+	if err != nil {
+		log.Printf("Failed to record revision %d of resource %s: %v", res.ResourceGenerationID, res.Id, err)
+		return
+	}
+
+	oldest := res.ResourceGenerationID - int64(svc.revisionHistoryLimit) + 1
+	if oldest <= 1 {
+		return
+	}
+	err = svc.store.DeleteRevisions(res.Id, oldest)
+	if err != nil {
+		log.Printf("Failed to delete the revisions of resource %s before %d: %v", res.Id, oldest, err)
+	}
+}
+
+func revisionNotKept(generationID int64) error {
+	return rpcerror.Error(codes.NotFound, rpcerror.ReasonRevisionNotKept, nil,
+		fmt.Sprintf("no revision of generation %d is kept for the resource", generationID))
+}
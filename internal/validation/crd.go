@@ -0,0 +1,159 @@
+package validation
+
+import (
+	"encoding/json"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// CRDGroupVersionKind is the kind of the CustomResourceDefinitions.
+var CRDGroupVersionKind = schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}
+
+// CustomResourceDefinition holds the parts of an apiextensions.k8s.io/v1
+// CustomResourceDefinition needed to validate its custom resources.
+type CustomResourceDefinition struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              CustomResourceDefinitionSpec `json:"spec"`
+}
+
+// CRDFromUnstructured reads a CustomResourceDefinition from its unstructured content.
+func CRDFromUnstructured(obj map[string]interface{}) (*CustomResourceDefinition, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	crd := &CustomResourceDefinition{}
+	if err := json.Unmarshal(data, crd); err != nil {
+		return nil, err
+	}
+	return crd, nil
+}
+
+type CustomResourceDefinitionSpec struct {
+	Group    string                            `json:"group"`
+	Names    CustomResourceDefinitionNames     `json:"names"`
+	Versions []CustomResourceDefinitionVersion `json:"versions"`
+}
+
+type CustomResourceDefinitionNames struct {
+	Kind string `json:"kind"`
+}
+
+type CustomResourceDefinitionVersion struct {
+	Name   string                    `json:"name"`
+	Served bool                      `json:"served"`
+	Schema *CustomResourceValidation `json:"schema,omitempty"`
+}
+
+type CustomResourceValidation struct {
+	OpenAPIV3Schema *spec.Schema `json:"openAPIV3Schema,omitempty"`
+}
+
+// Validate checks the parts of crd needed to validate its custom resources.
+func (crd *CustomResourceDefinition) Validate() field.ErrorList {
+	var allErrs field.ErrorList
+
+	if crd.GroupVersionKind() != CRDGroupVersionKind {
+		allErrs = append(allErrs, field.NotSupported(field.NewPath("kind"), crd.GroupVersionKind().String(),
+			[]string{CRDGroupVersionKind.String()}))
+	}
+	if crd.Name == "" {
+		allErrs = append(allErrs, field.Required(field.NewPath("metadata", "name"), ""))
+	}
+
+	specPath := field.NewPath("spec")
+	if crd.Spec.Group == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("group"), ""))
+	}
+	if crd.Spec.Names.Kind == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("names", "kind"), ""))
+	}
+	if len(crd.Spec.Versions) == 0 {
+		allErrs = append(allErrs, field.Required(specPath.Child("versions"), ""))
+	}
+	for i, version := range crd.Spec.Versions {
+		if version.Name == "" {
+			allErrs = append(allErrs, field.Required(specPath.Child("versions").Index(i).Child("name"), ""))
+		}
+	}
+
+	return allErrs
+}
+
+// Serves tells whether gvk is one of the kinds served by crd.
+func (crd *CustomResourceDefinition) Serves(gvk schema.GroupVersionKind) bool {
+	if gvk.Group != crd.Spec.Group || gvk.Kind != crd.Spec.Names.Kind {
+		return false
+	}
+	for _, version := range crd.Spec.Versions {
+		if version.Served && version.Name == gvk.Version {
+			return true
+		}
+	}
+	return false
+}
+
+// Schemas returns the schemas of the served versions, named like the Kubernetes API server
+// names them, e.g. "com.example.stable.v1.CronTab" for stable.example.com/v1 CronTab.
+// The schemas are copies, crd is left untouched and may be shared.
+func (crd *CustomResourceDefinition) Schemas() (map[string]*spec.Schema, error) {
+	groupParts := strings.Split(crd.Spec.Group, ".")
+	for i, j := 0, len(groupParts)-1; i < j; i, j = i+1, j-1 {
+		groupParts[i], groupParts[j] = groupParts[j], groupParts[i]
+	}
+	reversedGroup := strings.Join(groupParts, ".")
+
+	schemas := map[string]*spec.Schema{}
+	for _, version := range crd.Spec.Versions {
+		if !version.Served {
+			continue
+		}
+
+		s := &spec.Schema{}
+		if version.Schema != nil && version.Schema.OpenAPIV3Schema != nil {
+			copied, err := copySchema(version.Schema.OpenAPIV3Schema)
+			if err != nil {
+				return nil, err
+			}
+			s = copied
+		} else {
+			// without a schema, every field is accepted
+			s.Type = spec.StringOrArray{"object"}
+			s.AddExtension("x-kubernetes-preserve-unknown-fields", true)
+		}
+
+		// the API server sets the fields every object has
+		if s.Properties == nil {
+			s.Properties = map[string]spec.Schema{}
+		}
+		s.Properties["apiVersion"] = *spec.StringProperty()
+		s.Properties["kind"] = *spec.StringProperty()
+		s.Properties["metadata"] = *spec.RefSchema("#/components/schemas/" + objectMetaSchema)
+		s.AddExtension(gvkExtension, []interface{}{
+			map[string]interface{}{"group": crd.Spec.Group, "version": version.Name, "kind": crd.Spec.Names.Kind},
+		})
+
+		schemas[reversedGroup+"."+version.Name+"."+crd.Spec.Names.Kind] = s
+	}
+	return schemas, nil
+}
+
+// copySchema returns a deep copy of s.
+func copySchema(s *spec.Schema) (*spec.Schema, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &spec.Schema{}
+	if err := json.Unmarshal(data, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,485 @@
+package validation
+
+import (
+	"bytes"
+	"compress/gzip"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/api/validation/path"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/managedfields"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/kube-openapi/pkg/spec3"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+	"sigs.k8s.io/yaml"
+)
+
+const openAPISchemaDir = "OPENAPI_SCHEMA_DIR"
+
+// kubernetesSchemas is an OpenAPI v3 document of the built-in kinds of Kubernetes, gzipped.
+//
+//go:generate go run ../../hack/kubernetes-schemas -o kubernetes-schemas.json.gz
+//go:embed kubernetes-schemas.json.gz
+var kubernetesSchemas []byte
+
+// gvkExtension lists the kinds an OpenAPI schema describes.
+const gvkExtension = "x-kubernetes-group-version-kind"
+
+// objectMetaSchema is the name Kubernetes gives to the ObjectMeta schema.
+const objectMetaSchema = "io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"
+
+// Validator checks objects before they are sent to the consumers.
+type Validator struct {
+	schemas       map[string]*spec.Schema
+	kinds         map[schema.GroupVersionKind]string
+	typeConverter managedfields.TypeConverter
+}
+
+// NewValidator loads the schemas of the built-in Kubernetes kinds, then the ones found in the directory
+// named by OPENAPI_SCHEMA_DIR: OpenAPI v3 documents as served by the Kubernetes API server under
+// /openapi/v3, and CustomResourceDefinition manifests, in JSON or YAML.
+// The schemas of OPENAPI_SCHEMA_DIR replace the built-in ones of the same name.
+func NewValidator() (*Validator, error) {
+	v := &Validator{schemas: map[string]*spec.Schema{}}
+
+	if err := v.loadBuiltIn(); err != nil {
+		return nil, fmt.Errorf("failed to load the schemas of the built-in kinds: %w", err)
+	}
+
+	dir := os.Getenv(openAPISchemaDir)
+	if len(dir) != 0 {
+		if err := v.loadDir(dir); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := v.index(); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// NewCRDValidator returns a Validator of the custom resources defined by crds.
+func NewCRDValidator(crds ...*CustomResourceDefinition) (*Validator, error) {
+	v := &Validator{schemas: map[string]*spec.Schema{}}
+	for _, crd := range crds {
+		schemas, err := crd.Schemas()
+		if err != nil {
+			return nil, err
+		}
+		for name, s := range schemas {
+			v.schemas[name] = s
+		}
+	}
+
+	if err := v.index(); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (v *Validator) loadBuiltIn() error {
+	r, err := gzip.NewReader(bytes.NewReader(kubernetesSchemas))
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return v.load(data)
+}
+
+func (v *Validator) loadDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		switch filepath.Ext(entry.Name()) {
+		case ".json", ".yaml", ".yml":
+		default:
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		if err := v.load(data); err != nil {
+			return fmt.Errorf("failed to load schemas from %s: %w", entry.Name(), err)
+		}
+	}
+	return nil
+}
+
+// load adds the schemas of an OpenAPI v3 document or of a CustomResourceDefinition.
+func (v *Validator) load(data []byte) error {
+	data, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return err
+	}
+
+	var probe struct {
+		OpenAPI string `json:"openapi"`
+		Kind    string `json:"kind"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return err
+	}
+
+	switch {
+	case probe.OpenAPI != "":
+		var document spec3.OpenAPI
+		if err := json.Unmarshal(data, &document); err != nil {
+			return err
+		}
+		if document.Components != nil {
+			for name, s := range document.Components.Schemas {
+				v.schemas[name] = s
+			}
+		}
+		return nil
+	case probe.Kind == "CustomResourceDefinition":
+		var crd CustomResourceDefinition
+		if err := json.Unmarshal(data, &crd); err != nil {
+			return err
+		}
+		schemas, err := crd.Schemas()
+		if err != nil {
+			return err
+		}
+		for name, s := range schemas {
+			v.schemas[name] = s
+		}
+		return nil
+	default:
+		return errors.New("neither an OpenAPI v3 document nor a CustomResourceDefinition")
+	}
+}
+
+// index builds the structural schemas of the kinds described by the loaded schemas.
+func (v *Validator) index() error {
+	if _, ok := v.schemas[objectMetaSchema]; !ok {
+		// referenced by the CustomResourceDefinition schemas, accept any metadata
+		// when the core types are not loaded
+		metadata := &spec.Schema{}
+		metadata.Type = spec.StringOrArray{"object"}
+		metadata.AddExtension("x-kubernetes-preserve-unknown-fields", true)
+		v.schemas[objectMetaSchema] = metadata
+	}
+
+	v.kinds = map[schema.GroupVersionKind]string{}
+	for name, s := range v.schemas {
+		for _, gvk := range schemaKinds(s) {
+			v.kinds[gvk] = name
+		}
+	}
+
+	typeConverter, err := managedfields.NewTypeConverter(v.schemas, false)
+	if err != nil {
+		return err
+	}
+	v.typeConverter = typeConverter
+	return nil
+}
+
+// Knows tells whether gvk is a kind described by the loaded schemas, the built-in Kubernetes kinds included.
+func (v *Validator) Knows(gvk schema.GroupVersionKind) bool {
+	if _, ok := v.kinds[gvk]; ok {
+		return true
+	}
+	return gvk == CRDGroupVersionKind
+}
+
+// Validate returns the problems found in obj: missing or invalid metadata and, when the
+// schema of its kind is known, unknown fields, values of the wrong type and missing required fields.
+func (v *Validator) Validate(obj *unstructured.Unstructured) field.ErrorList {
+	allErrs := validateMetadata(obj)
+	if len(allErrs) > 0 {
+		return allErrs
+	}
+
+	if obj.GroupVersionKind() == CRDGroupVersionKind {
+		return ValidateCRD(obj)
+	}
+
+	name, ok := v.kinds[obj.GroupVersionKind()]
+	if !ok {
+		return nil
+	}
+
+	_, err := v.typeConverter.ObjectToTyped(obj)
+	var validationErrors typed.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		for _, e := range validationErrors {
+			allErrs = append(allErrs, field.Invalid(fieldPath(obj.Object, e.Path), field.OmitValueType{}, e.ErrorMessage))
+		}
+	} else if err != nil {
+		allErrs = append(allErrs, field.InternalError(nil, err))
+	}
+
+	r := requiredFields{schemas: v.schemas}
+	allErrs = append(allErrs, r.check(nil, v.schemas[name], obj.Object, 0)...)
+	return allErrs
+}
+
+// validateMetadata checks what every Kubernetes object needs, whatever its kind.
+func validateMetadata(obj *unstructured.Unstructured) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if obj.GetAPIVersion() == "" {
+		allErrs = append(allErrs, field.Required(field.NewPath("apiVersion"), ""))
+	} else if _, err := schema.ParseGroupVersion(obj.GetAPIVersion()); err != nil {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("apiVersion"), obj.GetAPIVersion(), err.Error()))
+	}
+	if obj.GetKind() == "" {
+		allErrs = append(allErrs, field.Required(field.NewPath("kind"), ""))
+	}
+
+	metadata := field.NewPath("metadata")
+	if obj.GetName() == "" {
+		allErrs = append(allErrs, field.Required(metadata.Child("name"), ""))
+	}
+	for _, msg := range path.ValidatePathSegmentName(obj.GetName(), false) {
+		allErrs = append(allErrs, field.Invalid(metadata.Child("name"), obj.GetName(), msg))
+	}
+	if obj.GetNamespace() != "" {
+		for _, msg := range utilvalidation.IsDNS1123Label(obj.GetNamespace()) {
+			allErrs = append(allErrs, field.Invalid(metadata.Child("namespace"), obj.GetNamespace(), msg))
+		}
+	}
+	allErrs = append(allErrs, metav1validation.ValidateLabels(obj.GetLabels(), metadata.Child("labels"))...)
+	allErrs = append(allErrs, apivalidation.ValidateAnnotations(obj.GetAnnotations(), metadata.Child("annotations"))...)
+
+	return allErrs
+}
+
+// ValidateCRD checks that the custom resources defined by the CustomResourceDefinition obj can be validated.
+func ValidateCRD(obj *unstructured.Unstructured) field.ErrorList {
+	crd, err := CRDFromUnstructured(obj.Object)
+	if err != nil {
+		return field.ErrorList{field.Invalid(field.NewPath("spec"), field.OmitValueType{}, err.Error())}
+	}
+	if allErrs := crd.Validate(); len(allErrs) > 0 {
+		return allErrs
+	}
+
+	if _, err := NewCRDValidator(crd); err != nil {
+		return field.ErrorList{field.Invalid(field.NewPath("spec", "versions"), field.OmitValueType{}, err.Error())}
+	}
+	return nil
+}
+
+// fieldPath turns the structured-merge-diff path of a value of obj into a field path, e.g.
+// .spec.containers[name="app"].image into spec.containers[0].image. The path is followed in obj,
+// which tells the field names holding dots apart and which item of a list a key designates.
+func fieldPath(obj interface{}, p string) *field.Path {
+	var fldPath *field.Path
+	for p != "" {
+		switch current := obj.(type) {
+		case map[string]interface{}:
+			name, ok := pathField(current, p)
+			if !ok {
+				return remainingPath(fldPath, p)
+			}
+			fldPath = fldPath.Child(name)
+			obj, p = current[name], p[len(name)+1:]
+		case []interface{}:
+			i, n, ok := pathItem(current, p)
+			if !ok {
+				return remainingPath(fldPath, p)
+			}
+			fldPath = fldPath.Index(i)
+			obj, p = current[i], p[n:]
+		default:
+			return remainingPath(fldPath, p)
+		}
+	}
+	return fldPath
+}
+
+// remainingPath appends the part of a path not found in the object as it is.
+func remainingPath(fldPath *field.Path, p string) *field.Path {
+	return fldPath.Child(strings.TrimPrefix(p, "."))
+}
+
+// pathField returns the field of m the path p starts with, the longest one when several match.
+func pathField(m map[string]interface{}, p string) (string, bool) {
+	if !strings.HasPrefix(p, ".") {
+		return "", false
+	}
+
+	name, found := "", false
+	for k := range m {
+		if pathStartsWith(p[1:], k) && (!found || len(k) > len(name)) {
+			name, found = k, true
+		}
+	}
+	return name, found
+}
+
+// pathItem returns the index of the item of items the path p starts with, and the length of its path element:
+// an index, e.g. [0], the value of an item of a set, e.g. [="a"], or the key of an item, e.g. [name="app"].
+func pathItem(items []interface{}, p string) (int, int, bool) {
+	names := keyNames(p)
+	for i, item := range items {
+		elements := []string{"[" + strconv.Itoa(i) + "]", "[=" + value.ToString(value.NewValueInterface(item)) + "]"}
+		if m, ok := item.(map[string]interface{}); ok && len(names) > 0 {
+			fields := make([]string, 0, len(names))
+			for _, name := range names {
+				fields = append(fields, name+"="+value.ToString(value.NewValueInterface(m[name])))
+			}
+			elements = append(elements, "["+strings.Join(fields, ",")+"]")
+		}
+
+		for _, element := range elements {
+			if pathStartsWith(p, element) {
+				return i, len(element), true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// keyNames returns the names of the fields of the key the path p starts with,
+// e.g. name and protocol for [name="http",protocol="TCP"].
+func keyNames(p string) []string {
+	if !strings.HasPrefix(p, "[") {
+		return nil
+	}
+
+	var names []string
+	start, quoted := 1, false
+	for i := 1; i < len(p); i++ {
+		switch {
+		case quoted && p[i] == '\\':
+			i++
+		case p[i] == '"':
+			quoted = !quoted
+		case quoted:
+		case p[i] == '=' && start >= 0:
+			names = append(names, p[start:i])
+			start = -1
+		case p[i] == ',':
+			start = i + 1
+		case p[i] == ']':
+			return names
+		}
+	}
+	return names
+}
+
+// pathStartsWith tells whether the path p starts with the path element element.
+func pathStartsWith(p, element string) bool {
+	if !strings.HasPrefix(p, element) {
+		return false
+	}
+	return len(p) == len(element) || p[len(element)] == '.' || p[len(element)] == '['
+}
+
+// maxSchemaDepth stops following recursive schemas.
+const maxSchemaDepth = 64
+
+// requiredFields checks that the required properties of the schemas are set.
+type requiredFields struct {
+	schemas map[string]*spec.Schema
+}
+
+func (r requiredFields) check(fldPath *field.Path, s *spec.Schema, value interface{}, depth int) field.ErrorList {
+	s = r.resolve(s)
+	if s == nil || depth > maxSchemaDepth {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				allErrs = append(allErrs, field.Required(fldPath.Child(name), ""))
+			}
+		}
+
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			property, ok := s.Properties[k]
+			if !ok {
+				if s.AdditionalProperties == nil || s.AdditionalProperties.Schema == nil {
+					continue
+				}
+				property = *s.AdditionalProperties.Schema
+			}
+			allErrs = append(allErrs, r.check(fldPath.Child(k), &property, v[k], depth+1)...)
+		}
+	case []interface{}:
+		if s.Items == nil || s.Items.Schema == nil {
+			return nil
+		}
+		for i, item := range v {
+			allErrs = append(allErrs, r.check(fldPath.Index(i), s.Items.Schema, item, depth+1)...)
+		}
+	}
+	return allErrs
+}
+
+// resolve follows the references of s, written as Kubernetes does either
+// directly or as the single element of allOf.
+func (r requiredFields) resolve(s *spec.Schema) *spec.Schema {
+	for i := 0; s != nil && i < maxSchemaDepth; i++ {
+		ref := s.Ref.String()
+		if ref == "" && len(s.AllOf) == 1 && len(s.Properties) == 0 {
+			ref = s.AllOf[0].Ref.String()
+		}
+		if ref == "" {
+			return s
+		}
+		s = r.schemas[ref[strings.LastIndex(ref, "/")+1:]]
+	}
+	return s
+}
+
+func schemaKinds(s *spec.Schema) []schema.GroupVersionKind {
+	list, ok := s.Extensions[gvkExtension].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	var kinds []schema.GroupVersionKind
+	for _, item := range list {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		group, _ := m["group"].(string)
+		version, _ := m["version"].(string)
+		kind, _ := m["kind"].(string)
+		if kind != "" {
+			kinds = append(kinds, schema.GroupVersionKind{Group: group, Version: version, Kind: kind})
+		}
+	}
+	return kinds
+}
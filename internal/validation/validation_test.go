@@ -0,0 +1,194 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func deployment(spec map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "web", "namespace": "default"},
+		"spec":       spec,
+	}}
+}
+
+func validDeploymentSpec() map[string]interface{} {
+	return map[string]interface{}{
+		"replicas": int64(2),
+		"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "web"}},
+		"template": map[string]interface{}{
+			"metadata": map[string]interface{}{"labels": map[string]interface{}{"app": "web"}},
+			"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"name": "sidecar", "image": "proxy"},
+					map[string]interface{}{"name": "app", "image": "web"},
+				},
+			},
+		},
+	}
+}
+
+func TestValidateBuiltInKinds(t *testing.T) {
+	v, err := NewValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if allErrs := v.Validate(deployment(validDeploymentSpec())); len(allErrs) > 0 {
+		t.Fatalf("valid Deployment rejected: %v", allErrs)
+	}
+
+	typo := deployment(validDeploymentSpec())
+	typo.Object["spce"] = typo.Object["spec"]
+	delete(typo.Object, "spec")
+
+	wrongType := deployment(validDeploymentSpec())
+	_ = unstructured.SetNestedField(wrongType.Object, "2", "spec", "replicas")
+
+	wrongContainer := deployment(validDeploymentSpec())
+	containers, _, _ := unstructured.NestedSlice(wrongContainer.Object, "spec", "template", "spec", "containers")
+	containers[1].(map[string]interface{})["image"] = int64(5)
+	_ = unstructured.SetNestedSlice(wrongContainer.Object, containers, "spec", "template", "spec", "containers")
+
+	missingSelector := deployment(validDeploymentSpec())
+	unstructured.RemoveNestedField(missingSelector.Object, "spec", "selector")
+
+	tests := []struct {
+		name string
+		obj  *unstructured.Unstructured
+		want *field.Path
+	}{
+		{"unknown field", typo, field.NewPath("spce")},
+		{"wrong type", wrongType, field.NewPath("spec", "replicas")},
+		{"wrong type in a keyed list", wrongContainer, field.NewPath("spec", "template", "spec", "containers").Index(1).Child("image")},
+		{"missing required field", missingSelector, field.NewPath("spec", "selector")},
+	}
+	for _, tt := range tests {
+		allErrs := v.Validate(tt.obj)
+		if len(allErrs) != 1 || allErrs[0].Field != tt.want.String() {
+			t.Errorf("%s: got %v, want a single error of %s", tt.name, allErrs, tt.want)
+		}
+	}
+}
+
+func TestKnows(t *testing.T) {
+	v, err := NewValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, gvk := range []schema.GroupVersionKind{
+		{Version: "v1", Kind: "ConfigMap"},
+		{Group: "apps", Version: "v1", Kind: "Deployment"},
+		{Group: "batch", Version: "v1", Kind: "CronJob"},
+		CRDGroupVersionKind,
+	} {
+		if !v.Knows(gvk) {
+			t.Errorf("built-in kind %s unknown", gvk)
+		}
+	}
+
+	for _, gvk := range []schema.GroupVersionKind{
+		{Group: "apps", Version: "v1beta1", Kind: "Deployment"},
+		{Group: "stable.example.com", Version: "v1", Kind: "CronTab"},
+	} {
+		if v.Knows(gvk) {
+			t.Errorf("got %s known", gvk)
+		}
+	}
+}
+
+func TestOpenAPISchemaDir(t *testing.T) {
+	dir := t.TempDir()
+	crd := `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: crontabs.stable.example.com
+spec:
+  group: stable.example.com
+  names:
+    kind: CronTab
+    plural: crontabs
+  scope: Namespaced
+  versions:
+  - name: v1
+    served: true
+    storage: true
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          spec:
+            type: object
+            required: [schedule]
+            properties:
+              schedule:
+                type: string
+`
+	if err := os.WriteFile(filepath.Join(dir, "crontab.yaml"), []byte(crd), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(openAPISchemaDir, dir)
+
+	v, err := NewValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cronTabKind := schema.GroupVersionKind{Group: "stable.example.com", Version: "v1", Kind: "CronTab"}
+	if !v.Knows(cronTabKind) || !v.Knows(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}) {
+		t.Fatal("kinds of the schema directory or built-in kinds unknown")
+	}
+
+	cronTab := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "stable.example.com/v1",
+		"kind":       "CronTab",
+		"metadata":   map[string]interface{}{"name": "backup"},
+		"spec":       map[string]interface{}{},
+	}}
+	allErrs := v.Validate(cronTab)
+	if len(allErrs) != 1 || allErrs[0].Type != field.ErrorTypeRequired || allErrs[0].Field != "spec.schedule" {
+		t.Errorf("got %v, want spec.schedule required", allErrs)
+	}
+}
+
+func TestFieldPath(t *testing.T) {
+	obj := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{"example.com/owner": "team", "example": map[string]interface{}{}},
+		},
+		"spec": map[string]interface{}{
+			"ports": []interface{}{
+				map[string]interface{}{"port": int64(80), "protocol": "TCP"},
+				map[string]interface{}{"port": int64(80), "protocol": "UDP", "name": "dns"},
+			},
+			"finalizers": []interface{}{"a", "b.c"},
+			"args":       []interface{}{"x", "y"},
+		},
+	}
+
+	tests := []struct {
+		path string
+		want *field.Path
+	}{
+		{".spec", field.NewPath("spec")},
+		{".metadata.annotations.example.com/owner", field.NewPath("metadata", "annotations", "example.com/owner")},
+		{`.spec.ports[port=80,protocol="UDP"].name`, field.NewPath("spec", "ports").Index(1).Child("name")},
+		{`.spec.finalizers[="b.c"]`, field.NewPath("spec", "finalizers").Index(1)},
+		{".spec.args[1]", field.NewPath("spec", "args").Index(1)},
+		// not in the object, kept as it is
+		{".spec.missing.field", field.NewPath("spec", "missing.field")},
+	}
+	for _, tt := range tests {
+		if got := fieldPath(obj, tt.path); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("path %s turned into %s, want %s", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+// Package watch broadcasts the changes made to consumers and resources to the watch APIs.
+package watch
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kube-orchestra/maestro/internal/db"
+	v1 "github.com/kube-orchestra/maestro/proto/api/v1"
+)
+
+type EventType string
+
+const (
+	Added    EventType = "ADDED"
+	Modified EventType = "MODIFIED"
+	Deleted  EventType = "DELETED"
+)
+
+// Event is a change to either a resource or a consumer.
+// The objects it references are shared between subscribers and must not be modified.
+type Event struct {
+	Type EventType
+	// Version identifies the event, watches resume after it.
+	Version  string
+	Resource *db.Resource
+	Consumer *v1.Consumer
+	// OldConsumer is the consumer before a Modified change.
+	OldConsumer *v1.Consumer
+}
+
+// historySize is the number of past events a watch can resume from.
+const historySize = 1024
+
+var (
+	// ErrExpired is returned when resuming from a version that is no longer in the history,
+	// the client has to list again.
+	ErrExpired = errors.New("resource version is too old, list again to get a current one")
+	// ErrInvalidVersion is returned when resuming from a version this server did not produce.
+	ErrInvalidVersion = errors.New("invalid resource version")
+)
+
+// Hub numbers the published events, keeps the most recent ones and fans them out to subscribers.
+// Versions embed the time at which the Hub was created, so that versions issued before
+// a restart are detected as expired instead of silently skipping events.
+type Hub struct {
+	mu          sync.Mutex
+	epoch       string
+	seq         uint64
+	history     []historyEntry
+	subscribers map[*Subscription]struct{}
+}
+
+type historyEntry struct {
+	seq   uint64
+	event Event
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		epoch:       strconv.FormatInt(time.Now().UnixNano(), 36),
+		subscribers: map[*Subscription]struct{}{},
+	}
+}
+
+// Subscription delivers the events published after the version it was created from.
+type Subscription struct {
+	hub    *Hub
+	events chan Event
+}
+
+// Events returns the channel of events. It is closed when the subscription is
+// stopped, or when the subscriber could not keep up with the published events;
+// it can then subscribe again from the version of the last event received.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Stop ends the subscription.
+func (s *Subscription) Stop() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	s.hub.unsubscribe(s)
+}
+
+// Version returns the version of the last published event.
+func (h *Hub) Version() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.version(h.seq)
+}
+
+// Publish assigns the next version to the event and delivers it to the subscribers.
+func (h *Hub) Publish(e Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.seq++
+	e.Version = h.version(h.seq)
+
+	h.history = append(h.history, historyEntry{seq: h.seq, event: e})
+	if len(h.history) > historySize {
+		h.history = h.history[len(h.history)-historySize:]
+	}
+
+	for s := range h.subscribers {
+		select {
+		case s.events <- e:
+		default:
+			// too slow, let it resume from where it is
+			h.unsubscribe(s)
+		}
+	}
+}
+
+// Subscribe returns a subscription to the events published after version,
+// or after the last published event when version is empty.
+func (h *Hub) Subscribe(version string) (*Subscription, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// room for the whole history and as many live events
+	s := &Subscription{hub: h, events: make(chan Event, 2*historySize)}
+
+	if version != "" {
+		seq, err := h.parseVersion(version)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(h.history) > 0 && seq+1 < h.history[0].seq {
+			return nil, ErrExpired
+		}
+
+		for _, entry := range h.history {
+			if entry.seq > seq {
+				s.events <- entry.event
+			}
+		}
+	}
+
+	h.subscribers[s] = struct{}{}
+	return s, nil
+}
+
+func (h *Hub) unsubscribe(s *Subscription) {
+	if _, ok := h.subscribers[s]; ok {
+		delete(h.subscribers, s)
+		close(s.events)
+	}
+}
+
+func (h *Hub) version(seq uint64) string {
+	return h.epoch + "-" + strconv.FormatUint(seq, 10)
+}
+
+func (h *Hub) parseVersion(version string) (uint64, error) {
+	epoch, seqString, found := strings.Cut(version, "-")
+	if !found {
+		return 0, ErrInvalidVersion
+	}
+
+	seq, err := strconv.ParseUint(seqString, 10, 64)
+	if err != nil {
+		return 0, ErrInvalidVersion
+	}
+
+	if epoch != h.epoch {
+		return 0, ErrExpired
+	}
+	if seq > h.seq {
+		return 0, fmt.Errorf("%w: %s is ahead of the last event", ErrInvalidVersion, version)
+	}
+
+	return seq, nil
+}
@@ -0,0 +1,101 @@
+package watch
+
+import (
+	"errors"
+	"testing"
+
+	v1 "github.com/kube-orchestra/maestro/proto/api/v1"
+)
+
+func publish(h *Hub, ids ...string) {
+	for _, id := range ids {
+		h.Publish(Event{Type: Added, Consumer: &v1.Consumer{Id: id}})
+	}
+}
+
+// receive returns the IDs of the consumers of the events pending on s.
+func receive(t *testing.T, s *Subscription) []string {
+	t.Helper()
+
+	ids := []string{}
+	for {
+		select {
+		case e, ok := <-s.Events():
+			if !ok {
+				t.Fatal("subscription closed")
+			}
+			ids = append(ids, e.Consumer.Id)
+		default:
+			return ids
+		}
+	}
+}
+
+func TestHubResume(t *testing.T) {
+	h := NewHub()
+	publish(h, "c1")
+	version := h.Version()
+	publish(h, "c2", "c3")
+
+	s, err := h.Subscribe(version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+	publish(h, "c4")
+
+	if got := receive(t, s); len(got) != 3 || got[0] != "c2" || got[1] != "c3" || got[2] != "c4" {
+		t.Errorf("resumed with events of %v, want c2, c3 and c4", got)
+	}
+
+	latest, err := h.Subscribe("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer latest.Stop()
+	if got := receive(t, latest); len(got) != 0 {
+		t.Errorf("got past events of %v subscribing from the last event", got)
+	}
+}
+
+func TestHubResumeErrors(t *testing.T) {
+	h := NewHub()
+	publish(h, "c1")
+	version := h.Version()
+	for i := 0; i < historySize+1; i++ {
+		publish(h, "c")
+	}
+
+	if _, err := h.Subscribe(version); !errors.Is(err, ErrExpired) {
+		t.Errorf("got %v resuming from a version out of the history, want ErrExpired", err)
+	}
+	if _, err := NewHub().Subscribe(h.Version()); !errors.Is(err, ErrExpired) {
+		t.Errorf("got %v resuming from a version of another hub, want ErrExpired", err)
+	}
+
+	for _, version := range []string{"garbage", h.epoch + "-x", h.version(h.seq + 1)} {
+		if _, err := h.Subscribe(version); !errors.Is(err, ErrInvalidVersion) {
+			t.Errorf("got %v resuming from %q, want ErrInvalidVersion", err, version)
+		}
+	}
+}
+
+func TestHubDropsSlowSubscribers(t *testing.T) {
+	h := NewHub()
+	s, err := h.Subscribe("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < cap(s.events)+1; i++ {
+		publish(h, "c")
+	}
+
+	received := 0
+	for range s.Events() {
+		received++
+	}
+	if received != cap(s.events) {
+		t.Errorf("received %d events before the subscription was closed, want %d", received, cap(s.events))
+	}
+}
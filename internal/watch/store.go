@@ -0,0 +1,131 @@
+package watch
+
+import (
+	"errors"
+
+	"github.com/kube-orchestra/maestro/internal/db"
+	v1 "github.com/kube-orchestra/maestro/proto/api/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// Store wraps a db.Store and publishes the changes made through it to a Hub.
+type Store struct {
+	db.Store
+	hub *Hub
+}
+
+func NewStore(store db.Store, hub *Hub) *Store {
+	return &Store{Store: store, hub: hub}
+}
+
+func (s *Store) CreateConsumer(c *v1.Consumer) error {
+	if err := s.Store.CreateConsumer(c); err != nil {
+		return err
+	}
+
+	s.hub.Publish(Event{Type: Added, Consumer: proto.Clone(c).(*v1.Consumer)})
+	return nil
+}
+
+func (s *Store) SetConsumerDeletionTimestamp(consumerID string, expected, deletionTimestamp int64) (*v1.Consumer, error) {
+	old, err := s.Store.SetConsumerDeletionTimestamp(consumerID, expected, deletionTimestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := proto.Clone(old).(*v1.Consumer)
+	updated.DeletionTimestamp = deletionTimestamp
+	s.hub.Publish(Event{Type: Modified, Consumer: updated, OldConsumer: old})
+	return old, nil
+}
+
+func (s *Store) UpdateConsumer(c *v1.Consumer) (*v1.Consumer, error) {
+	old, err := s.Store.UpdateConsumer(c)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := proto.Clone(old).(*v1.Consumer)
+	updated.Labels = proto.Clone(c).(*v1.Consumer).Labels
+	s.hub.Publish(Event{Type: Modified, Consumer: updated, OldConsumer: old})
+	return old, nil
+}
+
+func (s *Store) DeleteConsumer(consumerID string) error {
+	c, err := s.Store.GetConsumer(consumerID)
+	if isNotFound(err) {
+		// nothing to notify about
+		return s.Store.DeleteConsumer(consumerID)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := s.Store.DeleteConsumer(consumerID); err != nil {
+		return err
+	}
+
+	s.hub.Publish(Event{Type: Deleted, Consumer: c})
+	return nil
+}
+
+func (s *Store) CreateResource(r *db.Resource) error {
+	if err := s.Store.CreateResource(r); err != nil {
+		return err
+	}
+
+	s.hub.Publish(Event{Type: Added, Resource: r.DeepCopy()})
+	return nil
+}
+
+func (s *Store) UpdateResource(r *db.Resource, expectedGenerationID int64) error {
+	if err := s.Store.UpdateResource(r, expectedGenerationID); err != nil {
+		return err
+	}
+
+	// notify the stored resource, its status may be newer than the one of r
+	stored, err := s.Store.GetResource(r.Id)
+	if err != nil {
+		return err
+	}
+
+	s.hub.Publish(Event{Type: Modified, Resource: stored})
+	return nil
+}
+
+func (s *Store) DeleteResource(resourceID string) error {
+	r, err := s.Store.GetResource(resourceID)
+	if isNotFound(err) {
+		// nothing to notify about
+		return s.Store.DeleteResource(resourceID)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := s.Store.DeleteResource(resourceID); err != nil {
+		return err
+	}
+
+	s.hub.Publish(Event{Type: Deleted, Resource: r})
+	return nil
+}
+
+func (s *Store) SetStatusResource(consumerID, resourceID string, status *db.StatusMessage) error {
+	if err := s.Store.SetStatusResource(consumerID, resourceID, status); err != nil {
+		return err
+	}
+
+	r, err := s.Store.GetResource(resourceID)
+	if err != nil {
+		return err
+	}
+
+	s.hub.Publish(Event{Type: Modified, Resource: r})
+	return nil
+}
+
+func isNotFound(err error) bool {
+	var notFound *db.ErrorNotFound
+	return errors.As(err, &notFound)
+}
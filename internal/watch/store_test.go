@@ -0,0 +1,107 @@
+package watch
+
+import (
+	"testing"
+
+	"github.com/kube-orchestra/maestro/internal/db"
+	v1 "github.com/kube-orchestra/maestro/proto/api/v1"
+)
+
+// nextEvent returns the event pending on s, failing when there is none.
+func nextEvent(t *testing.T, s *Subscription) Event {
+	t.Helper()
+
+	select {
+	case e := <-s.Events():
+		return e
+	default:
+		t.Fatal("no event published")
+		return Event{}
+	}
+}
+
+func noEvent(t *testing.T, s *Subscription) {
+	t.Helper()
+
+	select {
+	case e := <-s.Events():
+		t.Errorf("got %s event of a failed change", e.Type)
+	default:
+	}
+}
+
+func TestStoreConsumerEvents(t *testing.T) {
+	h := NewHub()
+	store := NewStore(db.NewMemoryStore(), h)
+	s, err := h.Subscribe("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+
+	if err := store.CreateConsumer(&v1.Consumer{Id: "c1", Labels: []*v1.ConsumerLabel{{Key: "env", Value: "dev"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if e := nextEvent(t, s); e.Type != Added || e.Consumer.Id != "c1" {
+		t.Errorf("got %s event of %v creating the consumer, want ADDED", e.Type, e.Consumer)
+	}
+
+	if _, err := store.UpdateConsumer(&v1.Consumer{Id: "c1", Labels: []*v1.ConsumerLabel{{Key: "env", Value: "prod"}}}); err != nil {
+		t.Fatal(err)
+	}
+	e := nextEvent(t, s)
+	if e.Type != Modified || e.Consumer.Labels[0].Value != "prod" || e.OldConsumer.Labels[0].Value != "dev" {
+		t.Errorf("got %s event of %v from %v updating the consumer, want MODIFIED from env=dev to env=prod",
+			e.Type, e.Consumer, e.OldConsumer)
+	}
+
+	if _, err := store.SetConsumerDeletionTimestamp("c1", 0, 42); err != nil {
+		t.Fatal(err)
+	}
+	e = nextEvent(t, s)
+	if e.Type != Modified || e.Consumer.DeletionTimestamp != 42 || e.OldConsumer.DeletionTimestamp != 0 ||
+		e.Consumer.Labels[0].Value != "prod" {
+		t.Errorf("got %s event of %v from %v marking the consumer as deleting, want MODIFIED", e.Type, e.Consumer, e.OldConsumer)
+	}
+
+	if err := store.CreateConsumer(&v1.Consumer{Id: "c1"}); err == nil {
+		t.Fatal("created the consumer twice")
+	}
+	if _, err := store.UpdateConsumer(&v1.Consumer{Id: "c2"}); err == nil {
+		t.Fatal("updated a missing consumer")
+	}
+	if _, err := store.SetConsumerDeletionTimestamp("c1", 0, 43); err == nil {
+		t.Fatal("marked a consumer being deleted as deleting")
+	}
+	noEvent(t, s)
+}
+
+func TestStoreResourceEvents(t *testing.T) {
+	h := NewHub()
+	store := NewStore(db.NewMemoryStore(), h)
+	s, err := h.Subscribe("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+
+	r := &db.Resource{Id: "r1", ConsumerId: "c1", ResourceGenerationID: 1}
+	if err := store.CreateResource(r); err != nil {
+		t.Fatal(err)
+	}
+	if e := nextEvent(t, s); e.Type != Added || e.Resource.Id != "r1" {
+		t.Errorf("got %s event of %v creating the resource, want ADDED", e.Type, e.Resource)
+	}
+
+	if err := store.CreateResource(r); err == nil {
+		t.Fatal("created the resource twice")
+	}
+	noEvent(t, s)
+
+	if err := store.DeleteResource("r1"); err != nil {
+		t.Fatal(err)
+	}
+	if e := nextEvent(t, s); e.Type != Deleted || e.Resource.Id != "r1" {
+		t.Errorf("got %s event of %v deleting the resource, want DELETED", e.Type, e.Resource)
+	}
+}
@@ -19,9 +19,15 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	ConsumerService_Read_FullMethodName   = "/v1.ConsumerService/Read"
-	ConsumerService_Create_FullMethodName = "/v1.ConsumerService/Create"
-	ConsumerService_Update_FullMethodName = "/v1.ConsumerService/Update"
+	ConsumerService_Read_FullMethodName      = "/v1.ConsumerService/Read"
+	ConsumerService_List_FullMethodName      = "/v1.ConsumerService/List"
+	ConsumerService_Watch_FullMethodName     = "/v1.ConsumerService/Watch"
+	ConsumerService_Create_FullMethodName    = "/v1.ConsumerService/Create"
+	ConsumerService_Update_FullMethodName    = "/v1.ConsumerService/Update"
+	ConsumerService_Delete_FullMethodName    = "/v1.ConsumerService/Delete"
+	ConsumerService_PutCRD_FullMethodName    = "/v1.ConsumerService/PutCRD"
+	ConsumerService_ListCRDs_FullMethodName  = "/v1.ConsumerService/ListCRDs"
+	ConsumerService_DeleteCRD_FullMethodName = "/v1.ConsumerService/DeleteCRD"
 )
 
 // ConsumerServiceClient is the client API for ConsumerService service.
@@ -29,8 +35,21 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type ConsumerServiceClient interface {
 	Read(ctx context.Context, in *ConsumerReadRequest, opts ...grpc.CallOption) (*Consumer, error)
+	List(ctx context.Context, in *ConsumerListRequest, opts ...grpc.CallOption) (*ConsumerListResponse, error)
+	// Watch streams the registration, relabeling and removal of consumers.
+	// Through the gateway the events are streamed as newline delimited JSON objects.
+	// Only the changes made through the server serving the watch are streamed, the servers of
+	// a deployment with several of them do not share their changes.
+	Watch(ctx context.Context, in *ConsumerWatchRequest, opts ...grpc.CallOption) (ConsumerService_WatchClient, error)
 	Create(ctx context.Context, in *ConsumerCreateRequest, opts ...grpc.CallOption) (*Consumer, error)
 	Update(ctx context.Context, in *ConsumerUpdateRequest, opts ...grpc.CallOption) (*Consumer, error)
+	Delete(ctx context.Context, in *ConsumerDeleteRequest, opts ...grpc.CallOption) (*Consumer, error)
+	// PutCRD registers a CustomResourceDefinition installed on the cluster of the consumer,
+	// replacing the one with the same name. Resources of the kinds it serves are validated
+	// against its schemas.
+	PutCRD(ctx context.Context, in *ConsumerCRDPutRequest, opts ...grpc.CallOption) (*ConsumerCRD, error)
+	ListCRDs(ctx context.Context, in *ConsumerCRDListRequest, opts ...grpc.CallOption) (*ConsumerCRDListResponse, error)
+	DeleteCRD(ctx context.Context, in *ConsumerCRDDeleteRequest, opts ...grpc.CallOption) (*ConsumerCRD, error)
 }
 
 type consumerServiceClient struct {
@@ -50,6 +69,47 @@ func (c *consumerServiceClient) Read(ctx context.Context, in *ConsumerReadReques
 	return out, nil
 }
 
+func (c *consumerServiceClient) List(ctx context.Context, in *ConsumerListRequest, opts ...grpc.CallOption) (*ConsumerListResponse, error) {
+	out := new(ConsumerListResponse)
+	err := c.cc.Invoke(ctx, ConsumerService_List_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *consumerServiceClient) Watch(ctx context.Context, in *ConsumerWatchRequest, opts ...grpc.CallOption) (ConsumerService_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ConsumerService_ServiceDesc.Streams[0], ConsumerService_Watch_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &consumerServiceWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ConsumerService_WatchClient interface {
+	Recv() (*ConsumerWatchEvent, error)
+	grpc.ClientStream
+}
+
+type consumerServiceWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *consumerServiceWatchClient) Recv() (*ConsumerWatchEvent, error) {
+	m := new(ConsumerWatchEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *consumerServiceClient) Create(ctx context.Context, in *ConsumerCreateRequest, opts ...grpc.CallOption) (*Consumer, error) {
 	out := new(Consumer)
 	err := c.cc.Invoke(ctx, ConsumerService_Create_FullMethodName, in, out, opts...)
@@ -68,13 +128,62 @@ func (c *consumerServiceClient) Update(ctx context.Context, in *ConsumerUpdateRe
 	return out, nil
 }
 
+func (c *consumerServiceClient) Delete(ctx context.Context, in *ConsumerDeleteRequest, opts ...grpc.CallOption) (*Consumer, error) {
+	out := new(Consumer)
+	err := c.cc.Invoke(ctx, ConsumerService_Delete_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *consumerServiceClient) PutCRD(ctx context.Context, in *ConsumerCRDPutRequest, opts ...grpc.CallOption) (*ConsumerCRD, error) {
+	out := new(ConsumerCRD)
+	err := c.cc.Invoke(ctx, ConsumerService_PutCRD_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *consumerServiceClient) ListCRDs(ctx context.Context, in *ConsumerCRDListRequest, opts ...grpc.CallOption) (*ConsumerCRDListResponse, error) {
+	out := new(ConsumerCRDListResponse)
+	err := c.cc.Invoke(ctx, ConsumerService_ListCRDs_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *consumerServiceClient) DeleteCRD(ctx context.Context, in *ConsumerCRDDeleteRequest, opts ...grpc.CallOption) (*ConsumerCRD, error) {
+	out := new(ConsumerCRD)
+	err := c.cc.Invoke(ctx, ConsumerService_DeleteCRD_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ConsumerServiceServer is the server API for ConsumerService service.
 // All implementations must embed UnimplementedConsumerServiceServer
 // for forward compatibility
 type ConsumerServiceServer interface {
 	Read(context.Context, *ConsumerReadRequest) (*Consumer, error)
+	List(context.Context, *ConsumerListRequest) (*ConsumerListResponse, error)
+	// Watch streams the registration, relabeling and removal of consumers.
+	// Through the gateway the events are streamed as newline delimited JSON objects.
+	// Only the changes made through the server serving the watch are streamed, the servers of
+	// a deployment with several of them do not share their changes.
+	Watch(*ConsumerWatchRequest, ConsumerService_WatchServer) error
 	Create(context.Context, *ConsumerCreateRequest) (*Consumer, error)
 	Update(context.Context, *ConsumerUpdateRequest) (*Consumer, error)
+	Delete(context.Context, *ConsumerDeleteRequest) (*Consumer, error)
+	// PutCRD registers a CustomResourceDefinition installed on the cluster of the consumer,
+	// replacing the one with the same name. Resources of the kinds it serves are validated
+	// against its schemas.
+	PutCRD(context.Context, *ConsumerCRDPutRequest) (*ConsumerCRD, error)
+	ListCRDs(context.Context, *ConsumerCRDListRequest) (*ConsumerCRDListResponse, error)
+	DeleteCRD(context.Context, *ConsumerCRDDeleteRequest) (*ConsumerCRD, error)
 	mustEmbedUnimplementedConsumerServiceServer()
 }
 
@@ -85,12 +194,30 @@ type UnimplementedConsumerServiceServer struct {
 func (UnimplementedConsumerServiceServer) Read(context.Context, *ConsumerReadRequest) (*Consumer, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Read not implemented")
 }
+func (UnimplementedConsumerServiceServer) List(context.Context, *ConsumerListRequest) (*ConsumerListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (UnimplementedConsumerServiceServer) Watch(*ConsumerWatchRequest, ConsumerService_WatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
 func (UnimplementedConsumerServiceServer) Create(context.Context, *ConsumerCreateRequest) (*Consumer, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
 }
 func (UnimplementedConsumerServiceServer) Update(context.Context, *ConsumerUpdateRequest) (*Consumer, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Update not implemented")
 }
+func (UnimplementedConsumerServiceServer) Delete(context.Context, *ConsumerDeleteRequest) (*Consumer, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (UnimplementedConsumerServiceServer) PutCRD(context.Context, *ConsumerCRDPutRequest) (*ConsumerCRD, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PutCRD not implemented")
+}
+func (UnimplementedConsumerServiceServer) ListCRDs(context.Context, *ConsumerCRDListRequest) (*ConsumerCRDListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCRDs not implemented")
+}
+func (UnimplementedConsumerServiceServer) DeleteCRD(context.Context, *ConsumerCRDDeleteRequest) (*ConsumerCRD, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteCRD not implemented")
+}
 func (UnimplementedConsumerServiceServer) mustEmbedUnimplementedConsumerServiceServer() {}
 
 // UnsafeConsumerServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -122,6 +249,45 @@ func _ConsumerService_Read_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ConsumerService_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConsumerListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConsumerServiceServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConsumerService_List_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConsumerServiceServer).List(ctx, req.(*ConsumerListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConsumerService_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ConsumerWatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ConsumerServiceServer).Watch(m, &consumerServiceWatchServer{stream})
+}
+
+type ConsumerService_WatchServer interface {
+	Send(*ConsumerWatchEvent) error
+	grpc.ServerStream
+}
+
+type consumerServiceWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *consumerServiceWatchServer) Send(m *ConsumerWatchEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _ConsumerService_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ConsumerCreateRequest)
 	if err := dec(in); err != nil {
@@ -158,6 +324,78 @@ func _ConsumerService_Update_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ConsumerService_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConsumerDeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConsumerServiceServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConsumerService_Delete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConsumerServiceServer).Delete(ctx, req.(*ConsumerDeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConsumerService_PutCRD_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConsumerCRDPutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConsumerServiceServer).PutCRD(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConsumerService_PutCRD_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConsumerServiceServer).PutCRD(ctx, req.(*ConsumerCRDPutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConsumerService_ListCRDs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConsumerCRDListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConsumerServiceServer).ListCRDs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConsumerService_ListCRDs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConsumerServiceServer).ListCRDs(ctx, req.(*ConsumerCRDListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConsumerService_DeleteCRD_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConsumerCRDDeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConsumerServiceServer).DeleteCRD(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConsumerService_DeleteCRD_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConsumerServiceServer).DeleteCRD(ctx, req.(*ConsumerCRDDeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ConsumerService_ServiceDesc is the grpc.ServiceDesc for ConsumerService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -169,6 +407,10 @@ var ConsumerService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Read",
 			Handler:    _ConsumerService_Read_Handler,
 		},
+		{
+			MethodName: "List",
+			Handler:    _ConsumerService_List_Handler,
+		},
 		{
 			MethodName: "Create",
 			Handler:    _ConsumerService_Create_Handler,
@@ -177,7 +419,29 @@ var ConsumerService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Update",
 			Handler:    _ConsumerService_Update_Handler,
 		},
+		{
+			MethodName: "Delete",
+			Handler:    _ConsumerService_Delete_Handler,
+		},
+		{
+			MethodName: "PutCRD",
+			Handler:    _ConsumerService_PutCRD_Handler,
+		},
+		{
+			MethodName: "ListCRDs",
+			Handler:    _ConsumerService_ListCRDs_Handler,
+		},
+		{
+			MethodName: "DeleteCRD",
+			Handler:    _ConsumerService_DeleteCRD_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _ConsumerService_Watch_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/v1/consumer.proto",
 }
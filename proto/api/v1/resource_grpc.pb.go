@@ -19,9 +19,18 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	ResourceService_Read_FullMethodName   = "/v1.ResourceService/Read"
-	ResourceService_Create_FullMethodName = "/v1.ResourceService/Create"
-	ResourceService_Update_FullMethodName = "/v1.ResourceService/Update"
+	ResourceService_Read_FullMethodName          = "/v1.ResourceService/Read"
+	ResourceService_List_FullMethodName          = "/v1.ResourceService/List"
+	ResourceService_Watch_FullMethodName         = "/v1.ResourceService/Watch"
+	ResourceService_Create_FullMethodName        = "/v1.ResourceService/Create"
+	ResourceService_Update_FullMethodName        = "/v1.ResourceService/Update"
+	ResourceService_Patch_FullMethodName         = "/v1.ResourceService/Patch"
+	ResourceService_Apply_FullMethodName         = "/v1.ResourceService/Apply"
+	ResourceService_ListRevisions_FullMethodName = "/v1.ResourceService/ListRevisions"
+	ResourceService_Rollback_FullMethodName      = "/v1.ResourceService/Rollback"
+	ResourceService_Diff_FullMethodName          = "/v1.ResourceService/Diff"
+	ResourceService_ObservedDiff_FullMethodName  = "/v1.ResourceService/ObservedDiff"
+	ResourceService_Delete_FullMethodName        = "/v1.ResourceService/Delete"
 )
 
 // ResourceServiceClient is the client API for ResourceService service.
@@ -29,8 +38,29 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type ResourceServiceClient interface {
 	Read(ctx context.Context, in *ResourceReadRequest, opts ...grpc.CallOption) (*Resource, error)
+	List(ctx context.Context, in *ResourceListRequest, opts ...grpc.CallOption) (*ResourceListResponse, error)
+	// Watch streams the changes made to resources, including the status reported by consumers.
+	// Through the gateway the events are streamed as newline delimited JSON objects.
+	// Only the changes made through the server serving the watch are streamed, the servers of
+	// a deployment with several of them do not share their changes.
+	Watch(ctx context.Context, in *ResourceWatchRequest, opts ...grpc.CallOption) (ResourceService_WatchClient, error)
 	Create(ctx context.Context, in *ResourceCreateRequest, opts ...grpc.CallOption) (*Resource, error)
 	Update(ctx context.Context, in *ResourceUpdateRequest, opts ...grpc.CallOption) (*Resource, error)
+	Patch(ctx context.Context, in *ResourcePatchRequest, opts ...grpc.CallOption) (*Resource, error)
+	// Apply merges the object into the resource like a Kubernetes server-side apply,
+	// tracking which field manager owns each field in metadata.managedFields.
+	Apply(ctx context.Context, in *ResourceApplyRequest, opts ...grpc.CallOption) (*Resource, error)
+	// ListRevisions returns the last generations of the object of the resource.
+	ListRevisions(ctx context.Context, in *ResourceRevisionListRequest, opts ...grpc.CallOption) (*ResourceRevisionListResponse, error)
+	// Rollback writes the object of a past revision as a new generation and sends it to the consumer.
+	Rollback(ctx context.Context, in *ResourceRollbackRequest, opts ...grpc.CallOption) (*Resource, error)
+	// Diff returns the changes between two generations of the object of the resource.
+	Diff(ctx context.Context, in *ResourceDiffRequest, opts ...grpc.CallOption) (*ResourceDiffResponse, error)
+	// ObservedDiff returns the differences between the object of the resource and the content
+	// last reported by the consumer. The fields only set on the target, like the ones defaulted
+	// by the Kubernetes API server or the status, are left out.
+	ObservedDiff(ctx context.Context, in *ResourceObservedDiffRequest, opts ...grpc.CallOption) (*ResourceDiffResponse, error)
+	Delete(ctx context.Context, in *ResourceDeleteRequest, opts ...grpc.CallOption) (*Resource, error)
 }
 
 type resourceServiceClient struct {
@@ -50,6 +80,47 @@ func (c *resourceServiceClient) Read(ctx context.Context, in *ResourceReadReques
 	return out, nil
 }
 
+func (c *resourceServiceClient) List(ctx context.Context, in *ResourceListRequest, opts ...grpc.CallOption) (*ResourceListResponse, error) {
+	out := new(ResourceListResponse)
+	err := c.cc.Invoke(ctx, ResourceService_List_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *resourceServiceClient) Watch(ctx context.Context, in *ResourceWatchRequest, opts ...grpc.CallOption) (ResourceService_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ResourceService_ServiceDesc.Streams[0], ResourceService_Watch_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &resourceServiceWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ResourceService_WatchClient interface {
+	Recv() (*ResourceWatchEvent, error)
+	grpc.ClientStream
+}
+
+type resourceServiceWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *resourceServiceWatchClient) Recv() (*ResourceWatchEvent, error) {
+	m := new(ResourceWatchEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *resourceServiceClient) Create(ctx context.Context, in *ResourceCreateRequest, opts ...grpc.CallOption) (*Resource, error) {
 	out := new(Resource)
 	err := c.cc.Invoke(ctx, ResourceService_Create_FullMethodName, in, out, opts...)
@@ -68,13 +139,97 @@ func (c *resourceServiceClient) Update(ctx context.Context, in *ResourceUpdateRe
 	return out, nil
 }
 
+func (c *resourceServiceClient) Patch(ctx context.Context, in *ResourcePatchRequest, opts ...grpc.CallOption) (*Resource, error) {
+	out := new(Resource)
+	err := c.cc.Invoke(ctx, ResourceService_Patch_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *resourceServiceClient) Apply(ctx context.Context, in *ResourceApplyRequest, opts ...grpc.CallOption) (*Resource, error) {
+	out := new(Resource)
+	err := c.cc.Invoke(ctx, ResourceService_Apply_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *resourceServiceClient) ListRevisions(ctx context.Context, in *ResourceRevisionListRequest, opts ...grpc.CallOption) (*ResourceRevisionListResponse, error) {
+	out := new(ResourceRevisionListResponse)
+	err := c.cc.Invoke(ctx, ResourceService_ListRevisions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *resourceServiceClient) Rollback(ctx context.Context, in *ResourceRollbackRequest, opts ...grpc.CallOption) (*Resource, error) {
+	out := new(Resource)
+	err := c.cc.Invoke(ctx, ResourceService_Rollback_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *resourceServiceClient) Diff(ctx context.Context, in *ResourceDiffRequest, opts ...grpc.CallOption) (*ResourceDiffResponse, error) {
+	out := new(ResourceDiffResponse)
+	err := c.cc.Invoke(ctx, ResourceService_Diff_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *resourceServiceClient) ObservedDiff(ctx context.Context, in *ResourceObservedDiffRequest, opts ...grpc.CallOption) (*ResourceDiffResponse, error) {
+	out := new(ResourceDiffResponse)
+	err := c.cc.Invoke(ctx, ResourceService_ObservedDiff_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *resourceServiceClient) Delete(ctx context.Context, in *ResourceDeleteRequest, opts ...grpc.CallOption) (*Resource, error) {
+	out := new(Resource)
+	err := c.cc.Invoke(ctx, ResourceService_Delete_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ResourceServiceServer is the server API for ResourceService service.
 // All implementations must embed UnimplementedResourceServiceServer
 // for forward compatibility
 type ResourceServiceServer interface {
 	Read(context.Context, *ResourceReadRequest) (*Resource, error)
+	List(context.Context, *ResourceListRequest) (*ResourceListResponse, error)
+	// Watch streams the changes made to resources, including the status reported by consumers.
+	// Through the gateway the events are streamed as newline delimited JSON objects.
+	// Only the changes made through the server serving the watch are streamed, the servers of
+	// a deployment with several of them do not share their changes.
+	Watch(*ResourceWatchRequest, ResourceService_WatchServer) error
 	Create(context.Context, *ResourceCreateRequest) (*Resource, error)
 	Update(context.Context, *ResourceUpdateRequest) (*Resource, error)
+	Patch(context.Context, *ResourcePatchRequest) (*Resource, error)
+	// Apply merges the object into the resource like a Kubernetes server-side apply,
+	// tracking which field manager owns each field in metadata.managedFields.
+	Apply(context.Context, *ResourceApplyRequest) (*Resource, error)
+	// ListRevisions returns the last generations of the object of the resource.
+	ListRevisions(context.Context, *ResourceRevisionListRequest) (*ResourceRevisionListResponse, error)
+	// Rollback writes the object of a past revision as a new generation and sends it to the consumer.
+	Rollback(context.Context, *ResourceRollbackRequest) (*Resource, error)
+	// Diff returns the changes between two generations of the object of the resource.
+	Diff(context.Context, *ResourceDiffRequest) (*ResourceDiffResponse, error)
+	// ObservedDiff returns the differences between the object of the resource and the content
+	// last reported by the consumer. The fields only set on the target, like the ones defaulted
+	// by the Kubernetes API server or the status, are left out.
+	ObservedDiff(context.Context, *ResourceObservedDiffRequest) (*ResourceDiffResponse, error)
+	Delete(context.Context, *ResourceDeleteRequest) (*Resource, error)
 	mustEmbedUnimplementedResourceServiceServer()
 }
 
@@ -85,12 +240,39 @@ type UnimplementedResourceServiceServer struct {
 func (UnimplementedResourceServiceServer) Read(context.Context, *ResourceReadRequest) (*Resource, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Read not implemented")
 }
+func (UnimplementedResourceServiceServer) List(context.Context, *ResourceListRequest) (*ResourceListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (UnimplementedResourceServiceServer) Watch(*ResourceWatchRequest, ResourceService_WatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
 func (UnimplementedResourceServiceServer) Create(context.Context, *ResourceCreateRequest) (*Resource, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
 }
 func (UnimplementedResourceServiceServer) Update(context.Context, *ResourceUpdateRequest) (*Resource, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Update not implemented")
 }
+func (UnimplementedResourceServiceServer) Patch(context.Context, *ResourcePatchRequest) (*Resource, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Patch not implemented")
+}
+func (UnimplementedResourceServiceServer) Apply(context.Context, *ResourceApplyRequest) (*Resource, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Apply not implemented")
+}
+func (UnimplementedResourceServiceServer) ListRevisions(context.Context, *ResourceRevisionListRequest) (*ResourceRevisionListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRevisions not implemented")
+}
+func (UnimplementedResourceServiceServer) Rollback(context.Context, *ResourceRollbackRequest) (*Resource, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Rollback not implemented")
+}
+func (UnimplementedResourceServiceServer) Diff(context.Context, *ResourceDiffRequest) (*ResourceDiffResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Diff not implemented")
+}
+func (UnimplementedResourceServiceServer) ObservedDiff(context.Context, *ResourceObservedDiffRequest) (*ResourceDiffResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ObservedDiff not implemented")
+}
+func (UnimplementedResourceServiceServer) Delete(context.Context, *ResourceDeleteRequest) (*Resource, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
 func (UnimplementedResourceServiceServer) mustEmbedUnimplementedResourceServiceServer() {}
 
 // UnsafeResourceServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -122,6 +304,45 @@ func _ResourceService_Read_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ResourceService_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResourceListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ResourceServiceServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ResourceService_List_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ResourceServiceServer).List(ctx, req.(*ResourceListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ResourceService_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ResourceWatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ResourceServiceServer).Watch(m, &resourceServiceWatchServer{stream})
+}
+
+type ResourceService_WatchServer interface {
+	Send(*ResourceWatchEvent) error
+	grpc.ServerStream
+}
+
+type resourceServiceWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *resourceServiceWatchServer) Send(m *ResourceWatchEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _ResourceService_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ResourceCreateRequest)
 	if err := dec(in); err != nil {
@@ -158,6 +379,132 @@ func _ResourceService_Update_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ResourceService_Patch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResourcePatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ResourceServiceServer).Patch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ResourceService_Patch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ResourceServiceServer).Patch(ctx, req.(*ResourcePatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ResourceService_Apply_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResourceApplyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ResourceServiceServer).Apply(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ResourceService_Apply_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ResourceServiceServer).Apply(ctx, req.(*ResourceApplyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ResourceService_ListRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResourceRevisionListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ResourceServiceServer).ListRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ResourceService_ListRevisions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ResourceServiceServer).ListRevisions(ctx, req.(*ResourceRevisionListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ResourceService_Rollback_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResourceRollbackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ResourceServiceServer).Rollback(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ResourceService_Rollback_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ResourceServiceServer).Rollback(ctx, req.(*ResourceRollbackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ResourceService_Diff_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResourceDiffRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ResourceServiceServer).Diff(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ResourceService_Diff_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ResourceServiceServer).Diff(ctx, req.(*ResourceDiffRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ResourceService_ObservedDiff_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResourceObservedDiffRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ResourceServiceServer).ObservedDiff(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ResourceService_ObservedDiff_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ResourceServiceServer).ObservedDiff(ctx, req.(*ResourceObservedDiffRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ResourceService_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResourceDeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ResourceServiceServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ResourceService_Delete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ResourceServiceServer).Delete(ctx, req.(*ResourceDeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ResourceService_ServiceDesc is the grpc.ServiceDesc for ResourceService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -169,6 +516,10 @@ var ResourceService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Read",
 			Handler:    _ResourceService_Read_Handler,
 		},
+		{
+			MethodName: "List",
+			Handler:    _ResourceService_List_Handler,
+		},
 		{
 			MethodName: "Create",
 			Handler:    _ResourceService_Create_Handler,
@@ -177,7 +528,41 @@ var ResourceService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Update",
 			Handler:    _ResourceService_Update_Handler,
 		},
+		{
+			MethodName: "Patch",
+			Handler:    _ResourceService_Patch_Handler,
+		},
+		{
+			MethodName: "Apply",
+			Handler:    _ResourceService_Apply_Handler,
+		},
+		{
+			MethodName: "ListRevisions",
+			Handler:    _ResourceService_ListRevisions_Handler,
+		},
+		{
+			MethodName: "Rollback",
+			Handler:    _ResourceService_Rollback_Handler,
+		},
+		{
+			MethodName: "Diff",
+			Handler:    _ResourceService_Diff_Handler,
+		},
+		{
+			MethodName: "ObservedDiff",
+			Handler:    _ResourceService_ObservedDiff_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _ResourceService_Delete_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _ResourceService_Watch_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/v1/resource.proto",
 }